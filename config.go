@@ -1,7 +1,47 @@
 package main
 
+import "time"
+
 var (
 	AppVersions = [3]uint32{0, 1, 0}
 	AppPort     = "4001"
 	AppOs       = "GowaAPI"
+
+	// national numbers like "0812..." are completed with this country code unless the device sets its own, empty rejects them
+	AppDefaultCountryCode = ""
+
+	// incoming private messages matching one of these (case insensitive) suppress the sender
+	AppOptOutKeywords = []string{"STOP", "UNSUBSCRIBE", "BERHENTI"}
+
+	// import past conversations when a device pairs, messages older than the max age (0 for all) are skipped
+	AppHistorySync       = true
+	AppHistorySyncMaxAge = 90 * 24 * time.Hour
+
+	// media is kept in the media dir ("fs") or in an S3-compatible bucket ("s3"),
+	// incoming media of these message types up to the max size (in bytes) is downloaded automatically
+	AppMediaBackend = "fs"
+	AppMediaDir     = "./data/media"
+	AppMediaTypes   = []string{"image", "audio", "document", "sticker"}
+	AppMediaMaxSize = int64(16 << 20)
+
+	// media sent by url is only fetched from these hosts ("*.example.com" for subdomains), empty fetches nothing
+	AppMediaFetchHosts   = []string{}
+	AppMediaFetchTimeout = 30 * time.Second
+
+	// s3 media backend, defaults target a local minio whose credentials have to be set, a positive retention
+	// expires media through a bucket lifecycle rule next to the other rules of the bucket
+	AppS3Endpoint         = "http://localhost:9000"
+	AppS3Region           = "us-east-1"
+	AppS3Bucket           = "whatsapp-media"
+	AppS3Prefix           = "media"
+	AppS3AccessKey        = ""
+	AppS3SecretKey        = ""
+	AppS3PathStyle        = true
+	AppMediaRetentionDays = 90
+
+	// how long the whatsapp registration of a checked number is trusted before asking again, numbers that are
+	// not registered use the negative ttl, the cache is kept in memory and also in postgres when persisted
+	AppContactCacheTTL         = 24 * time.Hour
+	AppContactCacheNegativeTTL = time.Hour
+	AppContactCachePersist     = true
 )
@@ -41,3 +41,36 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(res.Status)
 	json.NewEncoder(w).Encode(res)
 }
+
+// RawHandler writes its own successful response (e.g. a file), errors are still answered as json.
+type RawHandler func(w http.ResponseWriter, r *http.Request) error
+
+func (h RawHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defer func() {
+		if err := recover(); err != nil {
+			json.NewEncoder(w).Encode(response.ResponseErrUnexpected)
+			return
+		}
+	}()
+
+	var handlerErr *response.ErrorResponse
+
+	err := h(w, r)
+	if errors.As(err, &handlerErr) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(handlerErr.Status)
+		resErr := &response.Response{
+			Status:  handlerErr.Status,
+			Message: handlerErr.Error(),
+			Result:  nil,
+			Error:   handlerErr,
+		}
+		json.NewEncoder(w).Encode(resErr)
+		return
+	}
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response.ResponseErrUnexpected)
+	}
+}
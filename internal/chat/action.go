@@ -0,0 +1,124 @@
+package chat
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/hrz8/whatsapp-api/internal/session"
+	"github.com/hrz8/whatsapp-api/pkg/response"
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// MutePayload mutes for Duration seconds, zero or omitted mutes the chat forever.
+type MutePayload struct {
+	ClientDeviceID string `json:"client_device_id"`
+	Duration       int64  `json:"duration"`
+}
+
+// action runs a chat action of the device, the chat comes from the path. Actions sync to the phone
+// and the other linked devices as whatsapp app state.
+func (h *Handler) action(r *http.Request, clientDeviceID string, message string, do func(cli *whatsmeow.Client, jid types.JID) error) (resp *response.Response, err error) {
+	cli, err := h.waCli.LoggedIn(clientDeviceID)
+	if errors.Is(err, whatsapp.ErrNotLogin) {
+		return nil, ErrRespNotLogin
+	}
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+	rcpt, err := whatsapp.ParseRecipient("", r.PathValue("jid"), h.waCli.DefaultCountry(clientDeviceID))
+	if err != nil || rcpt.Type == whatsapp.RecipientNewsletter {
+		return nil, ErrRespInvalidChat
+	}
+	err = do(cli, rcpt.JID)
+	if errors.Is(err, whatsapp.ErrInvalidMuteDuration) {
+		return nil, ErrRespInvalidMuteDuration
+	}
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: message,
+		Result:  map[string]any{"ok": true, "jid": rcpt.JID.String()},
+		Error:   nil,
+	}
+	return
+}
+
+// clientAction decodes the device from the body for actions without further options.
+func (h *Handler) clientAction(r *http.Request, message string, do func(clientDeviceID string, cli *whatsmeow.Client, jid types.JID) error) (resp *response.Response, err error) {
+	var p session.ClientPayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+	return h.action(r, p.ClientDeviceID, message, func(cli *whatsmeow.Client, jid types.JID) error {
+		return do(p.ClientDeviceID, cli, jid)
+	})
+}
+
+func (h *Handler) Archive(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	return h.clientAction(r, "chat archived", func(clientDeviceID string, cli *whatsmeow.Client, jid types.JID) error {
+		return h.waCli.ArchiveChat(cli, clientDeviceID, jid, true)
+	})
+}
+
+func (h *Handler) Unarchive(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	return h.clientAction(r, "chat unarchived", func(clientDeviceID string, cli *whatsmeow.Client, jid types.JID) error {
+		return h.waCli.ArchiveChat(cli, clientDeviceID, jid, false)
+	})
+}
+
+func (h *Handler) Pin(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	return h.clientAction(r, "chat pinned", func(clientDeviceID string, cli *whatsmeow.Client, jid types.JID) error {
+		return h.waCli.PinChat(cli, clientDeviceID, jid, true)
+	})
+}
+
+func (h *Handler) Unpin(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	return h.clientAction(r, "chat unpinned", func(clientDeviceID string, cli *whatsmeow.Client, jid types.JID) error {
+		return h.waCli.PinChat(cli, clientDeviceID, jid, false)
+	})
+}
+
+func (h *Handler) Mute(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p MutePayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+	return h.action(r, p.ClientDeviceID, "chat muted", func(cli *whatsmeow.Client, jid types.JID) error {
+		return h.waCli.MuteChat(cli, p.ClientDeviceID, jid, true, time.Duration(p.Duration)*time.Second)
+	})
+}
+
+func (h *Handler) Unmute(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	return h.clientAction(r, "chat unmuted", func(clientDeviceID string, cli *whatsmeow.Client, jid types.JID) error {
+		return h.waCli.MuteChat(cli, clientDeviceID, jid, false, 0)
+	})
+}
+
+// Unread marks the chat as unread on the phone, opening it there clears the mark but read receipts do not.
+func (h *Handler) Unread(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	return h.clientAction(r, "chat marked as unread", func(clientDeviceID string, cli *whatsmeow.Client, jid types.JID) error {
+		return h.waCli.MarkChatUnread(cli, clientDeviceID, jid, true)
+	})
+}
+
+func (h *Handler) Clear(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	return h.clientAction(r, "chat cleared", func(clientDeviceID string, cli *whatsmeow.Client, jid types.JID) error {
+		return h.waCli.ClearChat(cli, clientDeviceID, jid)
+	})
+}
+
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	clientDeviceID := r.URL.Query().Get("client_device_id")
+	return h.action(r, clientDeviceID, "chat deleted", func(cli *whatsmeow.Client, jid types.JID) error {
+		return h.waCli.DeleteChat(cli, clientDeviceID, jid)
+	})
+}
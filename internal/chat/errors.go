@@ -0,0 +1,61 @@
+package chat
+
+import (
+	"net/http"
+
+	"github.com/hrz8/whatsapp-api/internal/session"
+	"github.com/hrz8/whatsapp-api/pkg/response"
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
+)
+
+const (
+	EInvalidChat         response.ErrCode = "E015"
+	EHistorySyncNotFound response.ErrCode = "E016"
+	EInvalidSearch       response.ErrCode = "E017"
+	EInvalidChatPresence response.ErrCode = "E061"
+	ENoMessageIDs        response.ErrCode = "E063"
+	EInvalidMuteDuration response.ErrCode = "E064"
+)
+
+var (
+	ErrRespInvalidChat = &response.ErrorResponse{
+		E:      whatsapp.ErrRecipientNotFound,
+		Status: http.StatusBadRequest,
+		Data:   map[string]any{},
+		Code:   EInvalidChat,
+	}
+	ErrRespHistorySyncNotFound = &response.ErrorResponse{
+		E:      whatsapp.ErrHistorySyncNotFound,
+		Status: http.StatusNotFound,
+		Data:   map[string]any{},
+		Code:   EHistorySyncNotFound,
+	}
+	ErrRespInvalidChatPresence = &response.ErrorResponse{
+		E:      whatsapp.ErrInvalidChatPresence,
+		Status: http.StatusBadRequest,
+		Data:   map[string]any{"field": "state"},
+		Code:   EInvalidChatPresence,
+	}
+	ErrRespNoMessageIDs = &response.ErrorResponse{
+		E:      whatsapp.ErrNoMessageIDs,
+		Status: http.StatusBadRequest,
+		Data:   map[string]any{"field": "message_ids"},
+		Code:   ENoMessageIDs,
+	}
+	ErrRespInvalidMuteDuration = &response.ErrorResponse{
+		E:      whatsapp.ErrInvalidMuteDuration,
+		Status: http.StatusBadRequest,
+		Data:   map[string]any{"field": "duration"},
+		Code:   EInvalidMuteDuration,
+	}
+	ErrRespNotLogin = session.ErrRespNotLogin
+)
+
+func errRespInvalidSearch(err error, field string) *response.ErrorResponse {
+	return &response.ErrorResponse{
+		E:      err,
+		Status: http.StatusBadRequest,
+		Data:   map[string]any{"field": field},
+		Code:   EInvalidSearch,
+	}
+}
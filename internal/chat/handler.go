@@ -0,0 +1,159 @@
+package chat
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hrz8/whatsapp-api/pkg/response"
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
+)
+
+type Handler struct {
+	waCli *whatsapp.Client
+}
+
+func NewHandler(waCli *whatsapp.Client) *Handler {
+	return &Handler{waCli}
+}
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var archived *bool
+	if arg := r.URL.Query().Get("archived"); arg != "" {
+		value, err := strconv.ParseBool(arg)
+		if err != nil {
+			return nil, errRespInvalidSearch(errInvalidArchived, "archived")
+		}
+		archived = &value
+	}
+
+	limit, offset := response.ParsePage(r)
+	chats, err := h.waCli.Messages().ListChats(r.URL.Query().Get("client_device_id"), archived, limit, offset)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "chats found",
+		Result:  response.Page{Items: chats, Limit: limit, Offset: offset},
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) Messages(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	arg := r.PathValue("jid")
+	if arg == "" {
+		return nil, ErrRespInvalidChat
+	}
+	jid, err := whatsapp.ParseJID(arg)
+	if err != nil {
+		return nil, ErrRespInvalidChat
+	}
+
+	limit, offset := response.ParsePage(r)
+	messages, err := h.waCli.Messages().ListMessages(r.URL.Query().Get("client_device_id"), jid.ToNonAD().String(), limit, offset)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "messages found",
+		Result:  response.Page{Items: messages, Limit: limit, Offset: offset},
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) HistorySync(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	progress, err := h.waCli.HistorySyncs().Get(r.PathValue("client_device_id"))
+	if errors.Is(err, whatsapp.ErrHistorySyncNotFound) {
+		return nil, ErrRespHistorySyncNotFound
+	}
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "history sync progress found",
+		Result:  progress,
+		Error:   nil,
+	}
+	return
+}
+
+var (
+	errInvalidDate     = errors.New("date must be formatted as RFC3339 or YYYY-MM-DD")
+	errInvalidArchived = errors.New("archived must be either true or false")
+)
+
+func parseDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return time.Time{}, errInvalidDate
+	}
+	return t, nil
+}
+
+func parseJIDParam(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+	jid, err := whatsapp.ParseJID(s)
+	if err != nil {
+		return "", err
+	}
+	return jid.ToNonAD().String(), nil
+}
+
+func (h *Handler) Search(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	q := r.URL.Query()
+	limit, _ := response.ParsePage(r)
+	search := &whatsapp.MessageSearch{
+		ClientDeviceID: q.Get("client_device_id"),
+		Query:          q.Get("q"),
+		Type:           q.Get("type"),
+		Cursor:         q.Get("cursor"),
+		Limit:          limit,
+	}
+	if search.ChatJID, err = parseJIDParam(q.Get("chat")); err != nil {
+		return nil, errRespInvalidSearch(err, "chat")
+	}
+	if search.SenderJID, err = parseJIDParam(q.Get("sender")); err != nil {
+		return nil, errRespInvalidSearch(err, "sender")
+	}
+	if search.From, err = parseDate(q.Get("from")); err != nil {
+		return nil, errRespInvalidSearch(err, "from")
+	}
+	if search.To, err = parseDate(q.Get("to")); err != nil {
+		return nil, errRespInvalidSearch(err, "to")
+	}
+
+	results, next, err := h.waCli.Messages().Search(search)
+	switch {
+	case errors.Is(err, whatsapp.ErrSearchQueryEmpty):
+		return nil, errRespInvalidSearch(err, "q")
+	case errors.Is(err, whatsapp.ErrInvalidCursor):
+		return nil, errRespInvalidSearch(err, "cursor")
+	case err != nil:
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "messages found",
+		Result:  map[string]any{"items": results, "limit": limit, "next_cursor": next},
+		Error:   nil,
+	}
+	return
+}
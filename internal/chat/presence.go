@@ -0,0 +1,51 @@
+package chat
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/hrz8/whatsapp-api/pkg/response"
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
+)
+
+type PresencePayload struct {
+	ClientDeviceID string `json:"client_device_id"`
+	State          string `json:"state"`
+}
+
+// Presence shows the device composing, recording or paused in the chat, whatsapp clears it after a while on its own.
+func (h *Handler) Presence(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p PresencePayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	cli, err := h.waCli.LoggedIn(p.ClientDeviceID)
+	if errors.Is(err, whatsapp.ErrNotLogin) {
+		return nil, ErrRespNotLogin
+	}
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+	rcpt, err := whatsapp.ParseRecipient("", r.PathValue("jid"), h.waCli.DefaultCountry(p.ClientDeviceID))
+	if err != nil || rcpt.Type == whatsapp.RecipientNewsletter {
+		return nil, ErrRespInvalidChat
+	}
+	state, media, err := whatsapp.ParseChatPresence(p.State)
+	if err != nil {
+		return nil, ErrRespInvalidChatPresence
+	}
+	if err = cli.SendChatPresence(rcpt.JID, state, media); err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "chat presence sent",
+		Result:  map[string]any{"ok": true},
+		Error:   nil,
+	}
+	return
+}
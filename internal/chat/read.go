@@ -0,0 +1,73 @@
+package chat
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/hrz8/whatsapp-api/pkg/response"
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
+)
+
+type ReadPayload struct {
+	ClientDeviceID string   `json:"client_device_id"`
+	MessageIDs     []string `json:"message_ids"`
+	Played         bool     `json:"played"`
+}
+
+// Read marks incoming messages of the chat as read, and its voice notes as played when asked to.
+func (h *Handler) Read(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p ReadPayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	cli, err := h.waCli.LoggedIn(p.ClientDeviceID)
+	if errors.Is(err, whatsapp.ErrNotLogin) {
+		return nil, ErrRespNotLogin
+	}
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+	rcpt, err := whatsapp.ParseRecipient("", r.PathValue("jid"), h.waCli.DefaultCountry(p.ClientDeviceID))
+	if err != nil {
+		return nil, ErrRespInvalidChat
+	}
+	result, err := h.waCli.MarkRead(cli, p.ClientDeviceID, rcpt.JID, p.MessageIDs, p.Played)
+	if errors.Is(err, whatsapp.ErrNoMessageIDs) {
+		return nil, ErrRespNoMessageIDs
+	}
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "messages marked as read",
+		Result:  result,
+		Error:   nil,
+	}
+	return
+}
+
+// Receipts lists the delivered, read and played receipts of a message we sent, one per recipient.
+func (h *Handler) Receipts(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	clientDeviceID := r.URL.Query().Get("client_device_id")
+	rcpt, err := whatsapp.ParseRecipient("", r.PathValue("jid"), h.waCli.DefaultCountry(clientDeviceID))
+	if err != nil {
+		return nil, ErrRespInvalidChat
+	}
+	receipts, err := h.waCli.Receipts().List(clientDeviceID, rcpt.JID.ToNonAD().String(), r.PathValue("message_id"))
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "receipts found",
+		Result:  receipts,
+		Error:   nil,
+	}
+	return
+}
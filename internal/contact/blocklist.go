@@ -0,0 +1,67 @@
+package contact
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/hrz8/whatsapp-api/internal/session"
+	"github.com/hrz8/whatsapp-api/pkg/response"
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
+)
+
+func (h *Handler) Blocklist(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	cli, err := h.waCli.LoggedIn(r.URL.Query().Get("client_device_id"))
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	jids, err := whatsapp.Blocklist(cli)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "blocklist found",
+		Result:  jids,
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) Block(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	return h.block(r, true)
+}
+
+func (h *Handler) Unblock(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	return h.block(r, false)
+}
+
+// block answers with the updated blocklist, blocking also stops whatsapp from delivering the user's messages.
+func (h *Handler) block(r *http.Request, block bool) (resp *response.Response, err error) {
+	var p session.ClientPayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	cli, jid, err := h.user(r, p.ClientDeviceID, whatsapp.RecipientPhone)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	jids, err := whatsapp.Block(cli, jid, block)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	message := "contact blocked"
+	if !block {
+		message = "contact unblocked"
+	}
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: message,
+		Result:  jids,
+		Error:   nil,
+	}
+	return
+}
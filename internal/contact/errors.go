@@ -0,0 +1,104 @@
+package contact
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/hrz8/whatsapp-api/internal/device"
+	"github.com/hrz8/whatsapp-api/internal/session"
+	"github.com/hrz8/whatsapp-api/pkg/phone"
+	"github.com/hrz8/whatsapp-api/pkg/response"
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
+	"go.mau.fi/whatsmeow"
+)
+
+const (
+	ENoContacts      response.ErrCode = "E047"
+	ETooManyContacts response.ErrCode = "E048"
+	EUserNotFound    response.ErrCode = "E051"
+	ENotBusiness     response.ErrCode = "E052"
+	ENoPicture       response.ErrCode = "E053"
+	EPictureHidden   response.ErrCode = "E054"
+	ENotSubscribed   response.ErrCode = "E062"
+	ENoCacheTarget   response.ErrCode = "E065"
+)
+
+var (
+	ErrRespNoContacts = &response.ErrorResponse{
+		E:      whatsapp.ErrNoContacts,
+		Status: http.StatusBadRequest,
+		Data:   map[string]any{},
+		Code:   ENoContacts,
+	}
+	ErrRespTooManyContacts = &response.ErrorResponse{
+		E:      whatsapp.ErrTooManyContacts,
+		Status: http.StatusBadRequest,
+		Data:   map[string]any{"max": whatsapp.MaxContactCheck},
+		Code:   ETooManyContacts,
+	}
+	ErrRespUserNotFound = &response.ErrorResponse{
+		E:      whatsapp.ErrUserNotFound,
+		Status: http.StatusNotFound,
+		Data:   map[string]any{},
+		Code:   EUserNotFound,
+	}
+	ErrRespNotBusiness = &response.ErrorResponse{
+		E:      whatsapp.ErrNotBusiness,
+		Status: http.StatusNotFound,
+		Data:   map[string]any{},
+		Code:   ENotBusiness,
+	}
+	ErrRespNoPicture = &response.ErrorResponse{
+		E:      whatsapp.ErrNoPicture,
+		Status: http.StatusNotFound,
+		Data:   map[string]any{},
+		Code:   ENoPicture,
+	}
+	ErrRespPictureHidden = &response.ErrorResponse{
+		E:      whatsapp.ErrPictureHidden,
+		Status: http.StatusForbidden,
+		Data:   map[string]any{},
+		Code:   EPictureHidden,
+	}
+	ErrRespNotSubscribed = &response.ErrorResponse{
+		E:      whatsapp.ErrNotSubscribed,
+		Status: http.StatusNotFound,
+		Data:   map[string]any{},
+		Code:   ENotSubscribed,
+	}
+	ErrRespNoCacheTarget = &response.ErrorResponse{
+		E:      whatsapp.ErrNoCacheTarget,
+		Status: http.StatusBadRequest,
+		Data:   map[string]any{},
+		Code:   ENoCacheTarget,
+	}
+)
+
+func ErrResp(err error) error {
+	switch {
+	case errors.Is(err, whatsapp.ErrNotLogin):
+		return session.ErrRespNotLogin
+	case errors.Is(err, whatsapp.ErrNoContacts):
+		return ErrRespNoContacts
+	case errors.Is(err, whatsapp.ErrTooManyContacts):
+		return ErrRespTooManyContacts
+	case errors.Is(err, whatsapp.ErrUserNotFound):
+		return ErrRespUserNotFound
+	case errors.Is(err, whatsapp.ErrNotBusiness):
+		return ErrRespNotBusiness
+	case errors.Is(err, whatsapp.ErrNoPicture):
+		return ErrRespNoPicture
+	case errors.Is(err, whatsapp.ErrPictureHidden):
+		return ErrRespPictureHidden
+	case errors.Is(err, whatsapp.ErrNotSubscribed):
+		return ErrRespNotSubscribed
+	case errors.Is(err, whatsapp.ErrNoCacheTarget):
+		return ErrRespNoCacheTarget
+	case errors.Is(err, whatsmeow.ErrNoPushName):
+		return device.ErrRespNoPushName
+	case errors.Is(err, phone.ErrInvalid), errors.Is(err, whatsapp.ErrInvalidRecipient),
+		errors.Is(err, whatsapp.ErrInvalidRecipientType):
+		return session.RecipientErrResp(err)
+	}
+	return response.ErrRespServerUnexpected
+}
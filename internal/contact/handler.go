@@ -0,0 +1,185 @@
+package contact
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/hrz8/whatsapp-api/pkg/response"
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+type Handler struct {
+	waCli *whatsapp.Client
+}
+
+func NewHandler(waCli *whatsapp.Client) *Handler {
+	return &Handler{waCli}
+}
+
+type CheckPayload struct {
+	ClientDeviceID string   `json:"client_device_id"`
+	Phones         []string `json:"phones"`
+}
+
+// Check reports for every phone whether it is on whatsapp, invalid phones are reported instead of failing the whole check.
+func (h *Handler) Check(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p CheckPayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	cli, err := h.waCli.LoggedIn(p.ClientDeviceID)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	checks, err := h.waCli.CheckContacts(cli, p.Phones, h.waCli.DefaultCountry(p.ClientDeviceID))
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "contacts checked",
+		Result:  checks,
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) CacheStats(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "contact cache stats found",
+		Result:  h.waCli.ContactCache().Stats(),
+		Error:   nil,
+	}
+	return
+}
+
+// InvalidatePayload takes the client device id only to complete national numbers with its default country.
+// All flushes the whole cache and ignores the phones.
+type InvalidatePayload struct {
+	ClientDeviceID string   `json:"client_device_id"`
+	Phones         []string `json:"phones"`
+	All            bool     `json:"all"`
+}
+
+// InvalidateCache forgets the given phones, or the whole cache when asked to with all.
+func (h *Handler) InvalidateCache(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p InvalidatePayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	if err = h.waCli.InvalidateContacts(p.Phones, p.All, h.waCli.DefaultCountry(p.ClientDeviceID)); err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "contact cache invalidated",
+		Result:  map[string]any{"ok": true},
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	cli, err := h.waCli.LoggedIn(r.URL.Query().Get("client_device_id"))
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	contacts, err := whatsapp.Contacts(cli, r.URL.Query().Get("q"))
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	limit, offset := response.ParsePage(r)
+	items := contacts[min(offset, len(contacts)):min(offset+limit, len(contacts))]
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "contacts found",
+		Result:  response.Page{Items: items, Limit: limit, Offset: offset},
+		Error:   nil,
+	}
+	return
+}
+
+// user resolves the logged in device and the user of the jid path parameter, which can also be a phone number.
+func (h *Handler) user(r *http.Request, clientDeviceID string, typ whatsapp.RecipientType) (*whatsmeow.Client, types.JID, error) {
+	cli, err := h.waCli.LoggedIn(clientDeviceID)
+	if err != nil {
+		return nil, types.EmptyJID, err
+	}
+	rcpt, err := whatsapp.ParseRecipient(typ, r.PathValue("jid"), h.waCli.DefaultCountry(clientDeviceID))
+	if err != nil {
+		return nil, types.EmptyJID, err
+	}
+	return cli, rcpt.JID, nil
+}
+
+func (h *Handler) Info(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	cli, jid, err := h.user(r, r.URL.Query().Get("client_device_id"), whatsapp.RecipientPhone)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	info, err := whatsapp.GetUserInfo(cli, jid)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "user info found",
+		Result:  info,
+		Error:   nil,
+	}
+	return
+}
+
+// Picture also works for groups, preview=true returns the thumbnail instead of the full size picture.
+func (h *Handler) Picture(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	cli, jid, err := h.user(r, r.URL.Query().Get("client_device_id"), "")
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	preview := r.URL.Query().Get("preview") == "true"
+	picture, err := h.waCli.ProfilePicture(cli, r.URL.Query().Get("client_device_id"), jid, preview)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "profile picture found",
+		Result:  picture,
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) Business(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	cli, jid, err := h.user(r, r.URL.Query().Get("client_device_id"), whatsapp.RecipientPhone)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	profile, err := whatsapp.GetBusinessProfile(cli, jid)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "business profile found",
+		Result:  profile,
+		Error:   nil,
+	}
+	return
+}
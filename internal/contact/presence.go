@@ -0,0 +1,92 @@
+package contact
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/hrz8/whatsapp-api/internal/session"
+	"github.com/hrz8/whatsapp-api/pkg/response"
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
+)
+
+func (h *Handler) Presences(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	clientDeviceID := r.URL.Query().Get("client_device_id")
+	if _, err = h.waCli.LoggedIn(clientDeviceID); err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "presences found",
+		Result:  h.waCli.Presences(clientDeviceID),
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) Presence(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	clientDeviceID := r.URL.Query().Get("client_device_id")
+	_, jid, err := h.user(r, clientDeviceID, whatsapp.RecipientPhone)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	presence, err := h.waCli.Presence(clientDeviceID, jid)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "presence found",
+		Result:  presence,
+		Error:   nil,
+	}
+	return
+}
+
+// SubscribePresence answers with the presence known so far, updates follow as contact.presence events.
+// A device that is not available is marked available first and then shows as online to its contacts,
+// since whatsapp only shares presence with online devices. It is not marked available again after a reconnect.
+func (h *Handler) SubscribePresence(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p session.ClientPayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	cli, jid, err := h.user(r, p.ClientDeviceID, whatsapp.RecipientPhone)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	presence, err := h.waCli.SubscribePresence(cli, p.ClientDeviceID, jid)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "presence subscribed",
+		Result:  presence,
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) UnsubscribePresence(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	clientDeviceID := r.URL.Query().Get("client_device_id")
+	_, jid, err := h.user(r, clientDeviceID, whatsapp.RecipientPhone)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	if err = h.waCli.UnsubscribePresence(clientDeviceID, jid); err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "presence unsubscribed",
+		Result:  map[string]any{"ok": true},
+		Error:   nil,
+	}
+	return
+}
@@ -0,0 +1,96 @@
+package device
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/hrz8/whatsapp-api/internal/session"
+	"github.com/hrz8/whatsapp-api/pkg/avatar"
+	"github.com/hrz8/whatsapp-api/pkg/fetch"
+	"github.com/hrz8/whatsapp-api/pkg/response"
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
+	"go.mau.fi/whatsmeow"
+)
+
+const (
+	EInvalidCountryCode    response.ErrCode = "E050"
+	EInvalidPrivacySetting response.ErrCode = "E055"
+	EInvalidPushName       response.ErrCode = "E056"
+	EInvalidAbout          response.ErrCode = "E057"
+	EInvalidPicture        response.ErrCode = "E058"
+	EInvalidPresence       response.ErrCode = "E059"
+	ENoPushName            response.ErrCode = "E060"
+)
+
+var (
+	ErrInvalidCountryCode = errors.New("default country code must be 1 to 3 digits not starting with 0")
+)
+
+var (
+	ErrRespInvalidCountryCode = &response.ErrorResponse{
+		E:      ErrInvalidCountryCode,
+		Status: http.StatusBadRequest,
+		Data:   map[string]any{"field": "default_country_code"},
+		Code:   EInvalidCountryCode,
+	}
+	ErrRespInvalidPushName = &response.ErrorResponse{
+		E:      whatsapp.ErrInvalidPushName,
+		Status: http.StatusBadRequest,
+		Data:   map[string]any{"field": "push_name", "max_length": whatsapp.MaxPushNameLength},
+		Code:   EInvalidPushName,
+	}
+	ErrRespInvalidAbout = &response.ErrorResponse{
+		E:      whatsapp.ErrInvalidAbout,
+		Status: http.StatusBadRequest,
+		Data:   map[string]any{"field": "about", "max_length": whatsapp.MaxAboutLength},
+		Code:   EInvalidAbout,
+	}
+	ErrRespInvalidPicture = &response.ErrorResponse{
+		E:      avatar.ErrInvalidImage,
+		Status: http.StatusBadRequest,
+		Data:   map[string]any{"min_size": avatar.MinSize, "max_pixels": avatar.MaxPixels},
+		Code:   EInvalidPicture,
+	}
+	ErrRespInvalidPresence = &response.ErrorResponse{
+		E:      whatsapp.ErrInvalidPresence,
+		Status: http.StatusBadRequest,
+		Data:   map[string]any{"field": "presence"},
+		Code:   EInvalidPresence,
+	}
+	ErrRespNoPushName = &response.ErrorResponse{
+		E:      whatsmeow.ErrNoPushName,
+		Status: http.StatusConflict,
+		Data:   map[string]any{},
+		Code:   ENoPushName,
+	}
+)
+
+func ErrResp(err error) error {
+	var perr *whatsapp.PrivacySettingError
+	switch {
+	case errors.Is(err, whatsapp.ErrNotLogin):
+		return session.ErrRespNotLogin
+	case errors.Is(err, whatsapp.ErrInvalidPresence):
+		return ErrRespInvalidPresence
+	case errors.Is(err, whatsmeow.ErrNoPushName):
+		return ErrRespNoPushName
+	case errors.Is(err, whatsapp.ErrInvalidPushName):
+		return ErrRespInvalidPushName
+	case errors.Is(err, whatsapp.ErrInvalidAbout):
+		return ErrRespInvalidAbout
+	case errors.Is(err, avatar.ErrInvalidImage), errors.Is(err, whatsmeow.ErrInvalidImageFormat),
+		errors.Is(err, session.ErrInvalidMedia):
+		return ErrRespInvalidPicture
+	case errors.Is(err, fetch.ErrInvalidURL), errors.Is(err, fetch.ErrHostNotAllowed),
+		errors.Is(err, fetch.ErrTooLarge), errors.Is(err, fetch.ErrFetchFailed):
+		return session.MediaErrResp(err)
+	case errors.As(err, &perr):
+		return &response.ErrorResponse{
+			E:      perr,
+			Status: http.StatusBadRequest,
+			Data:   map[string]any{"field": perr.Name, "allowed": perr.Allowed},
+			Code:   EInvalidPrivacySetting,
+		}
+	}
+	return response.ErrRespServerUnexpected
+}
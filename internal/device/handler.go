@@ -0,0 +1,75 @@
+package device
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/hrz8/whatsapp-api/pkg/phone"
+	"github.com/hrz8/whatsapp-api/pkg/response"
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
+)
+
+type Handler struct {
+	waCli *whatsapp.Client
+}
+
+func NewHandler(waCli *whatsapp.Client) *Handler {
+	return &Handler{waCli}
+}
+
+func (h *Handler) Settings(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	settings, err := h.waCli.Settings().Get(r.PathValue("client_device_id"))
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "device settings found",
+		Result:  settings,
+		Error:   nil,
+	}
+	return
+}
+
+// SettingsPayload only changes the given fields, an empty country code falls back to the client wide default.
+type SettingsPayload struct {
+	DefaultCountryCode *string `json:"default_country_code"`
+	AutoMarkRead       *bool   `json:"auto_mark_read"`
+}
+
+func (h *Handler) SaveSettings(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p SettingsPayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	settings, err := h.waCli.Settings().Get(r.PathValue("client_device_id"))
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+	if p.DefaultCountryCode != nil {
+		cc := strings.TrimPrefix(strings.TrimSpace(*p.DefaultCountryCode), "+")
+		if cc != "" && !phone.ValidCountryCode(cc) {
+			return nil, ErrRespInvalidCountryCode
+		}
+		settings.DefaultCountryCode = cc
+	}
+	if p.AutoMarkRead != nil {
+		settings.AutoMarkRead = *p.AutoMarkRead
+	}
+	settings, err = h.waCli.Settings().Save(settings)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "device settings saved",
+		Result:  settings,
+		Error:   nil,
+	}
+	return
+}
@@ -0,0 +1,43 @@
+package device
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/hrz8/whatsapp-api/pkg/response"
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
+)
+
+type PresencePayload struct {
+	Presence string `json:"presence"`
+}
+
+// SetPresence marks the device online or offline, contacts only see it when the device has a push name.
+func (h *Handler) SetPresence(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p PresencePayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	clientDeviceID := r.PathValue("client_device_id")
+	cli, err := h.waCli.LoggedIn(clientDeviceID)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	presence, err := whatsapp.ParsePresence(p.Presence)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	if err = h.waCli.SetPresence(cli, clientDeviceID, presence); err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "presence updated",
+		Result:  map[string]any{"presence": presence},
+		Error:   nil,
+	}
+	return
+}
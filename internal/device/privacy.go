@@ -0,0 +1,82 @@
+package device
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/hrz8/whatsapp-api/pkg/response"
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func (h *Handler) Privacy(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	cli, err := h.waCli.LoggedIn(r.PathValue("client_device_id"))
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	settings, err := whatsapp.GetPrivacySettings(cli)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "privacy settings found",
+		Result:  settings,
+		Error:   nil,
+	}
+	return
+}
+
+// PrivacyPayload only changes the given settings.
+type PrivacyPayload struct {
+	GroupAdd     *types.PrivacySetting `json:"group_add"`
+	LastSeen     *types.PrivacySetting `json:"last_seen"`
+	Status       *types.PrivacySetting `json:"status"`
+	Profile      *types.PrivacySetting `json:"profile"`
+	ReadReceipts *types.PrivacySetting `json:"read_receipts"`
+	Online       *types.PrivacySetting `json:"online"`
+	CallAdd      *types.PrivacySetting `json:"call_add"`
+}
+
+func (p *PrivacyPayload) changes() []whatsapp.PrivacyChange {
+	var changes []whatsapp.PrivacyChange
+	add := func(name string, typ types.PrivacySettingType, value *types.PrivacySetting) {
+		if value != nil {
+			changes = append(changes, whatsapp.PrivacyChange{Name: name, Type: typ, Value: *value})
+		}
+	}
+	add("group_add", types.PrivacySettingTypeGroupAdd, p.GroupAdd)
+	add("last_seen", types.PrivacySettingTypeLastSeen, p.LastSeen)
+	add("status", types.PrivacySettingTypeStatus, p.Status)
+	add("profile", types.PrivacySettingTypeProfile, p.Profile)
+	add("read_receipts", types.PrivacySettingTypeReadReceipts, p.ReadReceipts)
+	add("online", types.PrivacySettingTypeOnline, p.Online)
+	add("call_add", types.PrivacySettingTypeCallAdd, p.CallAdd)
+	return changes
+}
+
+func (h *Handler) SetPrivacy(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p PrivacyPayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	cli, err := h.waCli.LoggedIn(r.PathValue("client_device_id"))
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	settings, err := whatsapp.SetPrivacySettings(cli, p.changes())
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "privacy settings saved",
+		Result:  settings,
+		Error:   nil,
+	}
+	return
+}
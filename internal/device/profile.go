@@ -0,0 +1,125 @@
+package device
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/hrz8/whatsapp-api/internal/session"
+	"github.com/hrz8/whatsapp-api/pkg/response"
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
+)
+
+func (h *Handler) Profile(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	cli, err := h.waCli.LoggedIn(r.PathValue("client_device_id"))
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	profile, err := whatsapp.GetOwnProfile(cli)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "profile found",
+		Result:  profile,
+		Error:   nil,
+	}
+	return
+}
+
+// ProfilePayload only changes the given fields, an empty about text clears it.
+type ProfilePayload struct {
+	PushName *string `json:"push_name"`
+	About    *string `json:"about"`
+}
+
+func (h *Handler) SetProfile(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p ProfilePayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	cli, err := h.waCli.LoggedIn(r.PathValue("client_device_id"))
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	if p.PushName != nil {
+		if err = whatsapp.SetPushName(cli, *p.PushName); err != nil {
+			return nil, ErrResp(err)
+		}
+	}
+	if p.About != nil {
+		if err = whatsapp.SetAbout(cli, *p.About); err != nil {
+			return nil, ErrResp(err)
+		}
+	}
+	profile, err := whatsapp.GetOwnProfile(cli)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "profile updated",
+		Result:  profile,
+		Error:   nil,
+	}
+	return
+}
+
+type PicturePayload struct {
+	Data string `json:"data"`
+	URL  string `json:"url"`
+}
+
+// SetPicture takes any jpeg, png or gif, it is cropped to a square and re-encoded as jpeg.
+func (h *Handler) SetPicture(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p PicturePayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	clientDeviceID := r.PathValue("client_device_id")
+	cli, err := h.waCli.LoggedIn(clientDeviceID)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	data, err := session.PictureData(r.Context(), h.waCli, p.Data, p.URL)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	pictureID, err := h.waCli.SetProfilePicture(cli, clientDeviceID, data)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "profile picture updated",
+		Result:  map[string]any{"picture_id": pictureID},
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) RemovePicture(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	clientDeviceID := r.PathValue("client_device_id")
+	cli, err := h.waCli.LoggedIn(clientDeviceID)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	if _, err = h.waCli.SetProfilePicture(cli, clientDeviceID, nil); err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "profile picture removed",
+		Result:  map[string]any{"ok": true},
+		Error:   nil,
+	}
+	return
+}
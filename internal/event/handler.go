@@ -0,0 +1,110 @@
+package event
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hrz8/whatsapp-api/internal/chat"
+	"github.com/hrz8/whatsapp-api/internal/session"
+	"github.com/hrz8/whatsapp-api/pkg/response"
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
+	waLog "go.mau.fi/whatsmeow/util/log"
+)
+
+// keepAlive is shorter than the idle timeout of common proxies.
+const keepAlive = 25 * time.Second
+
+type Handler struct {
+	waCli *whatsapp.Client
+	log   waLog.Logger
+}
+
+func NewHandler(waCli *whatsapp.Client) *Handler {
+	return &Handler{waCli, waLog.Stdout("Events", whatsapp.LogLevel, true)}
+}
+
+// Stream delivers events as server-sent events until the consumer disconnects.
+func (h *Handler) Stream(w http.ResponseWriter, r *http.Request) error {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return response.ErrRespServerUnexpected
+	}
+
+	events, unsubscribe := h.waCli.Events().Subscribe(r.URL.Query().Get("client_device_id"))
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return nil
+		case <-ticker.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case evt, ok := <-events:
+			if !ok {
+				return nil
+			}
+			data, err := json.Marshal(evt)
+			if err != nil {
+				h.log.Errorf("cannot encode event %d of type %v, skipping it: %v", evt.ID, evt.Type, err)
+				continue
+			}
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", evt.ID, evt.Type, data)
+		}
+		flusher.Flush()
+	}
+}
+
+type AckPayload struct {
+	ClientDeviceID string   `json:"client_device_id"`
+	ChatJID        string   `json:"chat_jid"`
+	MessageIDs     []string `json:"message_ids"`
+}
+
+// Ack is called by consumers once they handled message.received events, the messages are marked as read
+// when the device has auto_mark_read enabled and nothing happens otherwise. The chat can be a phone number.
+func (h *Handler) Ack(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p AckPayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	cli, err := h.waCli.LoggedIn(p.ClientDeviceID)
+	if errors.Is(err, whatsapp.ErrNotLogin) {
+		return nil, session.ErrRespNotLogin
+	}
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+	rcpt, err := whatsapp.ParseRecipient("", p.ChatJID, h.waCli.DefaultCountry(p.ClientDeviceID))
+	if err != nil {
+		return nil, chat.ErrRespInvalidChat
+	}
+	result, err := h.waCli.Ack(cli, p.ClientDeviceID, rcpt.JID, p.MessageIDs)
+	if errors.Is(err, whatsapp.ErrNoMessageIDs) {
+		return nil, chat.ErrRespNoMessageIDs
+	}
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "messages acknowledged",
+		Result:  map[string]any{"ok": true, "auto_mark_read": result != nil, "receipts": result},
+		Error:   nil,
+	}
+	return
+}
@@ -0,0 +1,169 @@
+package group
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/hrz8/whatsapp-api/pkg/response"
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func (h *Handler) ListCommunities(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	cli, err := h.waCli.LoggedIn(r.URL.Query().Get("client_device_id"))
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	infos, err := cli.GetJoinedGroups()
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	communities := make([]*whatsapp.Group, 0)
+	for _, info := range infos {
+		if info.IsParent {
+			communities = append(communities, whatsapp.NewGroup(info))
+		}
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "communities found",
+		Result:  communities,
+		Error:   nil,
+	}
+	return
+}
+
+type CreateCommunityPayload struct {
+	ClientDeviceID string `json:"client_device_id"`
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+}
+
+// CreateCommunity creates the community, whatsapp creates its announcement group along with it.
+func (h *Handler) CreateCommunity(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p CreateCommunityPayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	cli, err := h.waCli.LoggedIn(p.ClientDeviceID)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	if err = whatsapp.ValidateGroupName(p.Name); err != nil {
+		return nil, ErrResp(err)
+	}
+
+	info, err := cli.CreateGroup(whatsmeow.ReqCreateGroup{
+		Name:        p.Name,
+		GroupParent: types.GroupParent{IsParent: true},
+	})
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	if p.Description != "" {
+		if err = cli.SetGroupTopic(info.JID, "", "", p.Description); err != nil {
+			return nil, ErrResp(err)
+		}
+		info.Topic = p.Description
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusCreated,
+		Message: "community created",
+		Result:  whatsapp.NewGroup(info),
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) SubGroups(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	cli, jid, err := h.group(r, r.URL.Query().Get("client_device_id"))
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	if _, err = whatsapp.Community(cli, jid); err != nil {
+		return nil, ErrResp(err)
+	}
+	groups, err := cli.GetSubGroups(jid)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "community groups found",
+		Result:  whatsapp.NewCommunityGroups(groups),
+		Error:   nil,
+	}
+	return
+}
+
+type LinkPayload struct {
+	ClientDeviceID string `json:"client_device_id"`
+	Group          string `json:"group"`
+}
+
+func (h *Handler) LinkGroup(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p LinkPayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	cli, community, err := h.group(r, p.ClientDeviceID)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	child, err := whatsapp.ParseGroupJID(p.Group)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	if _, err = whatsapp.Community(cli, community); err != nil {
+		return nil, ErrResp(err)
+	}
+	info, err := cli.GetGroupInfo(child)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	if info.IsParent {
+		return nil, ErrRespInvalidCommunityGroup
+	}
+	if err = cli.LinkGroup(community, child); err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "group linked to community",
+		Result:  map[string]any{"ok": true},
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) UnlinkGroup(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	cli, community, err := h.group(r, r.URL.Query().Get("client_device_id"))
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	child, err := whatsapp.ParseGroupJID(r.PathValue("group"))
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	if err = cli.UnlinkGroup(community, child); err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "group unlinked from community",
+		Result:  map[string]any{"ok": true},
+		Error:   nil,
+	}
+	return
+}
@@ -0,0 +1,155 @@
+package group
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/hrz8/whatsapp-api/internal/session"
+	"github.com/hrz8/whatsapp-api/pkg/avatar"
+	"github.com/hrz8/whatsapp-api/pkg/fetch"
+	"github.com/hrz8/whatsapp-api/pkg/phone"
+	"github.com/hrz8/whatsapp-api/pkg/response"
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
+	"go.mau.fi/whatsmeow"
+)
+
+const (
+	EInvalidGroup          response.ErrCode = "E027"
+	EGroupNotFound         response.ErrCode = "E028"
+	ENotInGroup            response.ErrCode = "E029"
+	EInvalidParticipant    response.ErrCode = "E030"
+	EInvalidGroupName      response.ErrCode = "E031"
+	EInvalidGroupAction    response.ErrCode = "E032"
+	EGroupForbidden        response.ErrCode = "E033"
+	EInvalidGroupPicture   response.ErrCode = "E034"
+	EInviteLinkInvalid     response.ErrCode = "E035"
+	EInviteLinkRevoked     response.ErrCode = "E036"
+	EInviteUnauthorized    response.ErrCode = "E037"
+	EInvalidRequest        response.ErrCode = "E038"
+	EInvalidCommunityGroup response.ErrCode = "E046"
+)
+
+var ErrGroupForbidden = errors.New("not allowed to change the group, admin rights may be required")
+
+var (
+	ErrRespInvalidGroup = &response.ErrorResponse{
+		E:      whatsapp.ErrInvalidGroup,
+		Status: http.StatusBadRequest,
+		Data:   map[string]any{},
+		Code:   EInvalidGroup,
+	}
+	ErrRespGroupNotFound = &response.ErrorResponse{
+		E:      whatsmeow.ErrGroupNotFound,
+		Status: http.StatusNotFound,
+		Data:   map[string]any{},
+		Code:   EGroupNotFound,
+	}
+	ErrRespNotInGroup = &response.ErrorResponse{
+		E:      whatsmeow.ErrNotInGroup,
+		Status: http.StatusForbidden,
+		Data:   map[string]any{},
+		Code:   ENotInGroup,
+	}
+	ErrRespInvalidParticipant = &response.ErrorResponse{
+		E:      whatsapp.ErrInvalidParticipant,
+		Status: http.StatusBadRequest,
+		Data:   map[string]any{},
+		Code:   EInvalidParticipant,
+	}
+	ErrRespInvalidGroupName = &response.ErrorResponse{
+		E:      whatsapp.ErrInvalidGroupName,
+		Status: http.StatusBadRequest,
+		Data:   map[string]any{},
+		Code:   EInvalidGroupName,
+	}
+	ErrRespInvalidGroupAction = &response.ErrorResponse{
+		E:      whatsapp.ErrInvalidGroupAction,
+		Status: http.StatusBadRequest,
+		Data:   map[string]any{},
+		Code:   EInvalidGroupAction,
+	}
+	ErrRespGroupForbidden = &response.ErrorResponse{
+		E:      ErrGroupForbidden,
+		Status: http.StatusForbidden,
+		Data:   map[string]any{},
+		Code:   EGroupForbidden,
+	}
+	ErrRespInvalidGroupPicture = &response.ErrorResponse{
+		E:      whatsmeow.ErrInvalidImageFormat,
+		Status: http.StatusBadRequest,
+		Data:   map[string]any{},
+		Code:   EInvalidGroupPicture,
+	}
+	ErrRespInviteLinkInvalid = &response.ErrorResponse{
+		E:      whatsmeow.ErrInviteLinkInvalid,
+		Status: http.StatusBadRequest,
+		Data:   map[string]any{},
+		Code:   EInviteLinkInvalid,
+	}
+	ErrRespInviteLinkRevoked = &response.ErrorResponse{
+		E:      whatsmeow.ErrInviteLinkRevoked,
+		Status: http.StatusGone,
+		Data:   map[string]any{},
+		Code:   EInviteLinkRevoked,
+	}
+	ErrRespInviteUnauthorized = &response.ErrorResponse{
+		E:      whatsmeow.ErrGroupInviteLinkUnauthorized,
+		Status: http.StatusForbidden,
+		Data:   map[string]any{},
+		Code:   EInviteUnauthorized,
+	}
+	ErrRespInvalidRequest = &response.ErrorResponse{
+		E:      whatsapp.ErrInvalidRequestAction,
+		Status: http.StatusBadRequest,
+		Data:   map[string]any{},
+		Code:   EInvalidRequest,
+	}
+	ErrRespInvalidCommunityGroup = &response.ErrorResponse{
+		E:      whatsapp.ErrInvalidCommunityGroup,
+		Status: http.StatusBadRequest,
+		Data:   map[string]any{},
+		Code:   EInvalidCommunityGroup,
+	}
+)
+
+func ErrResp(err error) error {
+	switch {
+	case errors.Is(err, whatsapp.ErrNotLogin):
+		return session.ErrRespNotLogin
+	case errors.Is(err, whatsapp.ErrInvalidGroup):
+		return ErrRespInvalidGroup
+	case errors.Is(err, whatsapp.ErrInvalidParticipant):
+		return ErrRespInvalidParticipant
+	case errors.Is(err, phone.ErrInvalid):
+		return session.PhoneErrResp(err)
+	case errors.Is(err, whatsapp.ErrInvalidGroupName):
+		return ErrRespInvalidGroupName
+	case errors.Is(err, whatsapp.ErrInvalidGroupAction):
+		return ErrRespInvalidGroupAction
+	case errors.Is(err, whatsapp.ErrNotCommunity):
+		return session.ErrRespNotCommunity
+	case errors.Is(err, whatsapp.ErrInvalidCommunityGroup):
+		return ErrRespInvalidCommunityGroup
+	case errors.Is(err, whatsapp.ErrInvalidRequestAction):
+		return ErrRespInvalidRequest
+	case errors.Is(err, whatsmeow.ErrInviteLinkInvalid):
+		return ErrRespInviteLinkInvalid
+	case errors.Is(err, whatsmeow.ErrInviteLinkRevoked):
+		return ErrRespInviteLinkRevoked
+	case errors.Is(err, whatsmeow.ErrGroupInviteLinkUnauthorized):
+		return ErrRespInviteUnauthorized
+	case errors.Is(err, whatsmeow.ErrGroupNotFound), errors.Is(err, whatsmeow.ErrIQNotFound):
+		return ErrRespGroupNotFound
+	case errors.Is(err, whatsmeow.ErrNotInGroup):
+		return ErrRespNotInGroup
+	case errors.Is(err, whatsmeow.ErrIQForbidden), errors.Is(err, whatsmeow.ErrIQNotAuthorized):
+		return ErrRespGroupForbidden
+	case errors.Is(err, whatsmeow.ErrInvalidImageFormat), errors.Is(err, avatar.ErrInvalidImage),
+		errors.Is(err, session.ErrInvalidMedia):
+		return ErrRespInvalidGroupPicture
+	case errors.Is(err, fetch.ErrInvalidURL), errors.Is(err, fetch.ErrHostNotAllowed),
+		errors.Is(err, fetch.ErrTooLarge), errors.Is(err, fetch.ErrFetchFailed):
+		return session.MediaErrResp(err)
+	}
+	return response.ErrRespServerUnexpected
+}
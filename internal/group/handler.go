@@ -0,0 +1,336 @@
+package group
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/hrz8/whatsapp-api/internal/session"
+	"github.com/hrz8/whatsapp-api/pkg/response"
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+type Handler struct {
+	waCli *whatsapp.Client
+}
+
+func NewHandler(waCli *whatsapp.Client) *Handler {
+	return &Handler{waCli}
+}
+
+// group resolves the logged in device and the group of the request path.
+func (h *Handler) group(r *http.Request, clientDeviceID string) (*whatsmeow.Client, types.JID, error) {
+	cli, err := h.waCli.LoggedIn(clientDeviceID)
+	if err != nil {
+		return nil, types.EmptyJID, err
+	}
+	jid, err := whatsapp.ParseGroupJID(r.PathValue("jid"))
+	return cli, jid, err
+}
+
+type CreatePayload struct {
+	ClientDeviceID string   `json:"client_device_id"`
+	Name           string   `json:"name"`
+	Participants   []string `json:"participants"`
+}
+
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p CreatePayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	cli, err := h.waCli.LoggedIn(p.ClientDeviceID)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	if err = whatsapp.ValidateGroupName(p.Name); err != nil {
+		return nil, ErrResp(err)
+	}
+	participants, err := whatsapp.ParseParticipants(p.Participants, h.waCli.DefaultCountry(p.ClientDeviceID))
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	info, err := cli.CreateGroup(whatsmeow.ReqCreateGroup{Name: p.Name, Participants: participants})
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusCreated,
+		Message: "group created",
+		Result:  whatsapp.NewGroup(info),
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	cli, err := h.waCli.LoggedIn(r.URL.Query().Get("client_device_id"))
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	infos, err := cli.GetJoinedGroups()
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	groups := make([]*whatsapp.Group, 0, len(infos))
+	for _, info := range infos {
+		groups = append(groups, whatsapp.NewGroup(info))
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "groups found",
+		Result:  groups,
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	cli, jid, err := h.group(r, r.URL.Query().Get("client_device_id"))
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	info, err := cli.GetGroupInfo(jid)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "group found",
+		Result:  whatsapp.NewGroup(info),
+		Error:   nil,
+	}
+	return
+}
+
+type ParticipantsPayload struct {
+	ClientDeviceID string   `json:"client_device_id"`
+	Action         string   `json:"action"`
+	Participants   []string `json:"participants"`
+}
+
+func (h *Handler) UpdateParticipants(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p ParticipantsPayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	cli, jid, err := h.group(r, p.ClientDeviceID)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	action, err := whatsapp.ParseParticipantAction(p.Action)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	participants, err := whatsapp.ParseParticipants(p.Participants, h.waCli.DefaultCountry(p.ClientDeviceID))
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	if len(participants) == 0 {
+		return nil, ErrRespInvalidParticipant
+	}
+
+	changed, err := cli.UpdateGroupParticipants(jid, participants, action)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "group participants updated",
+		Result:  whatsapp.NewGroupParticipants(changed),
+		Error:   nil,
+	}
+	return
+}
+
+type SubjectPayload struct {
+	ClientDeviceID string `json:"client_device_id"`
+	Name           string `json:"name"`
+}
+
+func (h *Handler) SetSubject(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p SubjectPayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	cli, jid, err := h.group(r, p.ClientDeviceID)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	if err = whatsapp.ValidateGroupName(p.Name); err != nil {
+		return nil, ErrResp(err)
+	}
+	if err = cli.SetGroupName(jid, p.Name); err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "group subject updated",
+		Result:  map[string]any{"ok": true},
+		Error:   nil,
+	}
+	return
+}
+
+type DescriptionPayload struct {
+	ClientDeviceID string `json:"client_device_id"`
+	Description    string `json:"description"`
+}
+
+func (h *Handler) SetDescription(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p DescriptionPayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	cli, jid, err := h.group(r, p.ClientDeviceID)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	// an empty description removes it
+	if err = cli.SetGroupTopic(jid, "", "", p.Description); err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "group description updated",
+		Result:  map[string]any{"ok": true},
+		Error:   nil,
+	}
+	return
+}
+
+type PicturePayload struct {
+	ClientDeviceID string `json:"client_device_id"`
+	Data           string `json:"data"`
+	URL            string `json:"url"`
+}
+
+func (h *Handler) SetPicture(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p PicturePayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	cli, jid, err := h.group(r, p.ClientDeviceID)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	data, err := session.PictureData(r.Context(), h.waCli, p.Data, p.URL)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	pictureID, err := cli.SetGroupPhoto(jid, data)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "group picture updated",
+		Result:  map[string]any{"picture_id": pictureID},
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) RemovePicture(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	cli, jid, err := h.group(r, r.URL.Query().Get("client_device_id"))
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	if _, err = cli.SetGroupPhoto(jid, nil); err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "group picture removed",
+		Result:  map[string]any{"ok": true},
+		Error:   nil,
+	}
+	return
+}
+
+// SettingsPayload only changes the settings that are given.
+type SettingsPayload struct {
+	ClientDeviceID string `json:"client_device_id"`
+	Announce       *bool  `json:"announce"`
+	Locked         *bool  `json:"locked"`
+}
+
+func (h *Handler) SetSettings(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p SettingsPayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	cli, jid, err := h.group(r, p.ClientDeviceID)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	if p.Announce != nil {
+		if err = cli.SetGroupAnnounce(jid, *p.Announce); err != nil {
+			return nil, ErrResp(err)
+		}
+	}
+	if p.Locked != nil {
+		if err = cli.SetGroupLocked(jid, *p.Locked); err != nil {
+			return nil, ErrResp(err)
+		}
+	}
+	info, err := cli.GetGroupInfo(jid)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "group settings updated",
+		Result:  whatsapp.NewGroup(info),
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) Leave(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p session.ClientPayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	cli, jid, err := h.group(r, p.ClientDeviceID)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	if err = cli.LeaveGroup(jid); err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "group left",
+		Result:  map[string]any{"ok": true},
+		Error:   nil,
+	}
+	return
+}
@@ -0,0 +1,168 @@
+package group
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/hrz8/whatsapp-api/internal/session"
+	"github.com/hrz8/whatsapp-api/pkg/response"
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
+	"go.mau.fi/whatsmeow"
+)
+
+// inviteCode accepts the bare code as well as the full chat.whatsapp.com link.
+func inviteCode(r *http.Request) string {
+	return strings.TrimPrefix(strings.TrimSpace(r.PathValue("code")), whatsmeow.InviteLinkPrefix)
+}
+
+func (h *Handler) InviteLink(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	cli, jid, err := h.group(r, r.URL.Query().Get("client_device_id"))
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	link, err := cli.GetGroupInviteLink(jid, false)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "group invite link found",
+		Result:  map[string]any{"link": link, "code": strings.TrimPrefix(link, whatsmeow.InviteLinkPrefix)},
+		Error:   nil,
+	}
+	return
+}
+
+// ResetInviteLink revokes the current invite link, anyone who has not joined through it yet needs the new one.
+func (h *Handler) ResetInviteLink(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p session.ClientPayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	cli, jid, err := h.group(r, p.ClientDeviceID)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	link, err := cli.GetGroupInviteLink(jid, true)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "group invite link reset",
+		Result:  map[string]any{"link": link, "code": strings.TrimPrefix(link, whatsmeow.InviteLinkPrefix)},
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) PreviewInvite(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	cli, err := h.waCli.LoggedIn(r.URL.Query().Get("client_device_id"))
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	info, err := cli.GetGroupInfoFromLink(inviteCode(r))
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "group invite found",
+		Result:  whatsapp.NewGroup(info),
+		Error:   nil,
+	}
+	return
+}
+
+// JoinInvite joins the group, for groups with approval mode this only files a join request.
+func (h *Handler) JoinInvite(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p session.ClientPayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	cli, err := h.waCli.LoggedIn(p.ClientDeviceID)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	jid, err := cli.JoinGroupWithLink(inviteCode(r))
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "group joined",
+		Result:  map[string]any{"jid": jid.String()},
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) Requests(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	cli, jid, err := h.group(r, r.URL.Query().Get("client_device_id"))
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	requests, err := cli.GetGroupRequestParticipants(jid)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	jids := make([]string, 0, len(requests))
+	for _, requester := range requests {
+		jids = append(jids, requester.String())
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "group join requests found",
+		Result:  jids,
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) UpdateRequests(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p ParticipantsPayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	cli, jid, err := h.group(r, p.ClientDeviceID)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	action, err := whatsapp.ParseRequestAction(p.Action)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	participants, err := whatsapp.ParseParticipants(p.Participants, h.waCli.DefaultCountry(p.ClientDeviceID))
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	if len(participants) == 0 {
+		return nil, ErrRespInvalidParticipant
+	}
+
+	changed, err := cli.UpdateGroupRequestParticipants(jid, participants, action)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "group join requests updated",
+		Result:  whatsapp.NewGroupParticipants(changed),
+		Error:   nil,
+	}
+	return
+}
@@ -0,0 +1,57 @@
+package media
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/hrz8/whatsapp-api/pkg/response"
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
+)
+
+const (
+	EMediaNotFound response.ErrCode = "E018"
+	EMediaDisabled response.ErrCode = "E019"
+	EMediaNoURL    response.ErrCode = "E020"
+	EInvalidExpiry response.ErrCode = "E021"
+)
+
+var ErrInvalidExpiry = errors.New("expires must be a number of seconds between 1 and 604800")
+
+var (
+	ErrRespMediaNotFound = &response.ErrorResponse{
+		E:      whatsapp.ErrMediaNotFound,
+		Status: http.StatusNotFound,
+		Data:   map[string]any{},
+		Code:   EMediaNotFound,
+	}
+	ErrRespMediaDisabled = &response.ErrorResponse{
+		E:      whatsapp.ErrMediaDisabled,
+		Status: http.StatusNotImplemented,
+		Data:   map[string]any{},
+		Code:   EMediaDisabled,
+	}
+	ErrRespMediaNoURL = &response.ErrorResponse{
+		E:      whatsapp.ErrMediaNoURL,
+		Status: http.StatusNotImplemented,
+		Data:   map[string]any{},
+		Code:   EMediaNoURL,
+	}
+	ErrRespInvalidExpiry = &response.ErrorResponse{
+		E:      ErrInvalidExpiry,
+		Status: http.StatusBadRequest,
+		Data:   map[string]any{},
+		Code:   EInvalidExpiry,
+	}
+)
+
+func ErrResp(err error) error {
+	switch {
+	case errors.Is(err, whatsapp.ErrMediaNotFound):
+		return ErrRespMediaNotFound
+	case errors.Is(err, whatsapp.ErrMediaDisabled):
+		return ErrRespMediaDisabled
+	case errors.Is(err, whatsapp.ErrMediaNoURL):
+		return ErrRespMediaNoURL
+	}
+	return response.ErrRespServerUnexpected
+}
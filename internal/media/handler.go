@@ -0,0 +1,66 @@
+package media
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hrz8/whatsapp-api/pkg/blob"
+	"github.com/hrz8/whatsapp-api/pkg/response"
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
+)
+
+const DefaultURLExpiry = 15 * time.Minute
+
+type Handler struct {
+	waCli *whatsapp.Client
+}
+
+func NewHandler(waCli *whatsapp.Client) *Handler {
+	return &Handler{waCli}
+}
+
+func (h *Handler) Download(w http.ResponseWriter, r *http.Request) error {
+	media, rc, err := h.waCli.OpenMedia(r.Context(), r.PathValue("id"))
+	if err != nil {
+		return ErrResp(err)
+	}
+	defer rc.Close()
+
+	w.Header().Set("Content-Type", media.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(media.Size, 10))
+	// content addressed, the bytes behind an id never change
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("ETag", `"`+media.ID+`"`)
+	w.WriteHeader(http.StatusOK)
+	_, err = io.Copy(w, rc)
+	return err
+}
+
+func (h *Handler) URL(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	expires := DefaultURLExpiry
+	if v := r.URL.Query().Get("expires"); v != "" {
+		secs, err := strconv.Atoi(v)
+		if err != nil || secs <= 0 || time.Duration(secs)*time.Second > blob.MaxPresignExpiry {
+			return nil, ErrRespInvalidExpiry
+		}
+		expires = time.Duration(secs) * time.Second
+	}
+
+	url, err := h.waCli.MediaURL(r.Context(), r.PathValue("id"), expires)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "success create media url",
+		Result: map[string]any{
+			"url":        url,
+			"expires_at": time.Now().Add(expires).UTC(),
+		},
+		Error: nil,
+	}
+	return
+}
@@ -0,0 +1,39 @@
+package rule
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/hrz8/whatsapp-api/pkg/autoreply"
+	"github.com/hrz8/whatsapp-api/pkg/response"
+)
+
+const (
+	ERuleNotFound response.ErrCode = "E011"
+	ERuleInvalid  response.ErrCode = "E012"
+)
+
+var (
+	ErrRespRuleNotFound = &response.ErrorResponse{
+		E:      autoreply.ErrRuleNotFound,
+		Status: http.StatusNotFound,
+		Data:   map[string]any{},
+		Code:   ERuleNotFound,
+	}
+)
+
+func ErrResp(err error) error {
+	var validationErr *autoreply.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return &response.ErrorResponse{
+			E:      validationErr.E,
+			Status: http.StatusBadRequest,
+			Data:   map[string]any{"field": validationErr.Field, "reason": validationErr.Reason},
+			Code:   ERuleInvalid,
+		}
+	case errors.Is(err, autoreply.ErrRuleNotFound):
+		return ErrRespRuleNotFound
+	}
+	return response.ErrRespServerUnexpected
+}
@@ -0,0 +1,157 @@
+package rule
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/hrz8/whatsapp-api/pkg/autoreply"
+	"github.com/hrz8/whatsapp-api/pkg/response"
+)
+
+type Handler struct {
+	engine *autoreply.Engine
+}
+
+func NewHandler(engine *autoreply.Engine) *Handler {
+	return &Handler{engine}
+}
+
+type RulePayload struct {
+	ClientDeviceID  string           `json:"client_device_id"`
+	Name            string           `json:"name"`
+	Priority        int              `json:"priority"`
+	Enabled         *bool            `json:"enabled"`
+	Match           autoreply.Match  `json:"match"`
+	Action          autoreply.Action `json:"action"`
+	CooldownSeconds int              `json:"cooldown_seconds"`
+}
+
+func (p *RulePayload) toRule() *autoreply.Rule {
+	enabled := true
+	if p.Enabled != nil {
+		enabled = *p.Enabled
+	}
+	return &autoreply.Rule{
+		ClientDeviceID:  p.ClientDeviceID,
+		Name:            p.Name,
+		Priority:        p.Priority,
+		Enabled:         enabled,
+		Match:           p.Match,
+		Action:          p.Action,
+		CooldownSeconds: p.CooldownSeconds,
+	}
+}
+
+func ruleID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		return 0, autoreply.ErrRuleNotFound
+	}
+	return id, nil
+}
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	rules, err := h.engine.Rules().List(r.URL.Query().Get("client_device_id"))
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "rules found",
+		Result:  rules,
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	id, err := ruleID(r)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	rule, err := h.engine.Rules().Get(id)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "rule found",
+		Result:  rule,
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p RulePayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	rule := p.toRule()
+	if err = rule.Validate(); err != nil {
+		return nil, ErrResp(err)
+	}
+	if err = h.engine.Rules().Create(rule); err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusCreated,
+		Message: "rule created",
+		Result:  rule,
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	id, err := ruleID(r)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	var p RulePayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	rule := p.toRule()
+	rule.ID = id
+	if err = rule.Validate(); err != nil {
+		return nil, ErrResp(err)
+	}
+	if err = h.engine.Rules().Update(rule); err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "rule updated",
+		Result:  rule,
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	id, err := ruleID(r)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+	if err = h.engine.Rules().Delete(id); err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "rule deleted",
+		Result:  map[string]any{"ok": true},
+		Error:   nil,
+	}
+	return
+}
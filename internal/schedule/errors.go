@@ -0,0 +1,39 @@
+package schedule
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/hrz8/whatsapp-api/pkg/autoreply"
+	"github.com/hrz8/whatsapp-api/pkg/response"
+)
+
+const (
+	EScheduleNotFound response.ErrCode = "E013"
+	EScheduleInvalid  response.ErrCode = "E014"
+)
+
+var (
+	ErrRespScheduleNotFound = &response.ErrorResponse{
+		E:      autoreply.ErrScheduleNotFound,
+		Status: http.StatusNotFound,
+		Data:   map[string]any{},
+		Code:   EScheduleNotFound,
+	}
+)
+
+func ErrResp(err error) error {
+	var validationErr *autoreply.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return &response.ErrorResponse{
+			E:      validationErr.E,
+			Status: http.StatusBadRequest,
+			Data:   map[string]any{"field": validationErr.Field, "reason": validationErr.Reason},
+			Code:   EScheduleInvalid,
+		}
+	case errors.Is(err, autoreply.ErrScheduleNotFound):
+		return ErrRespScheduleNotFound
+	}
+	return response.ErrRespServerUnexpected
+}
@@ -0,0 +1,118 @@
+package schedule
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/hrz8/whatsapp-api/pkg/autoreply"
+	"github.com/hrz8/whatsapp-api/pkg/response"
+)
+
+type Handler struct {
+	engine *autoreply.Engine
+}
+
+func NewHandler(engine *autoreply.Engine) *Handler {
+	return &Handler{engine}
+}
+
+type SchedulePayload struct {
+	Enabled             *bool                           `json:"enabled"`
+	Timezone            string                          `json:"timezone"`
+	Weekly              map[string][]autoreply.Interval `json:"weekly"`
+	Holidays            []autoreply.Holiday             `json:"holidays"`
+	AwayMessage         string                          `json:"away_message"`
+	AwayCooldownSeconds int                             `json:"away_cooldown_seconds"`
+}
+
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	schedule, err := h.engine.Schedules().Get(r.PathValue("client_device_id"))
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "schedule found",
+		Result:  schedule,
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) Save(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p SchedulePayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	enabled := true
+	if p.Enabled != nil {
+		enabled = *p.Enabled
+	}
+	weekly := p.Weekly
+	if weekly == nil {
+		weekly = map[string][]autoreply.Interval{}
+	}
+	holidays := p.Holidays
+	if holidays == nil {
+		holidays = []autoreply.Holiday{}
+	}
+	schedule := &autoreply.Schedule{
+		ClientDeviceID:      r.PathValue("client_device_id"),
+		Enabled:             enabled,
+		Timezone:            p.Timezone,
+		Weekly:              weekly,
+		Holidays:            holidays,
+		AwayMessage:         p.AwayMessage,
+		AwayCooldownSeconds: p.AwayCooldownSeconds,
+	}
+	if err = schedule.Validate(); err != nil {
+		return nil, ErrResp(err)
+	}
+	if err = h.engine.Schedules().Save(schedule); err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "schedule saved",
+		Result:  schedule,
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	err = h.engine.Schedules().Delete(r.PathValue("client_device_id"))
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "schedule deleted",
+		Result:  map[string]any{"ok": true},
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) IsOpen(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	clientDeviceID := r.PathValue("client_device_id")
+	now := time.Now()
+	open, err := h.engine.IsOpen(clientDeviceID, now)
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "schedule checked",
+		Result:  map[string]any{"client_device_id": clientDeviceID, "open": open, "checked_at": now},
+		Error:   nil,
+	}
+	return
+}
@@ -4,19 +4,39 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/hrz8/whatsapp-api/pkg/fetch"
+	"github.com/hrz8/whatsapp-api/pkg/phone"
 	"github.com/hrz8/whatsapp-api/pkg/response"
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
 )
 
 const (
-	EAlreadyConnected  response.ErrCode = "E001"
-	ENotLogin          response.ErrCode = "E002"
-	ERecipientNotFound response.ErrCode = "E003"
+	EAlreadyConnected    response.ErrCode = "E001"
+	ENotLogin            response.ErrCode = "E002"
+	ERecipientNotFound   response.ErrCode = "E003"
+	ERecipientSuppressed response.ErrCode = "E008"
+	EInvalidMedia        response.ErrCode = "E022"
+	EMediaFetchFailed    response.ErrCode = "E023"
+	EMediaHostNotAllowed response.ErrCode = "E024"
+	EMediaTooLarge       response.ErrCode = "E025"
+	EInvalidMediaURL     response.ErrCode = "E026"
+	EInvalidRecipient    response.ErrCode = "E039"
+	ENotGroupMember      response.ErrCode = "E040"
+	EGroupAdminsOnly     response.ErrCode = "E041"
+	ENotNewsletterAdmin  response.ErrCode = "E042"
+	EMentionAllNotGroup  response.ErrCode = "E043"
+	ENotCommunity        response.ErrCode = "E044"
+	ENoAnnouncementGroup response.ErrCode = "E045"
+	EInvalidPhone        response.ErrCode = "E049"
+	EMessageAndTemplate  response.ErrCode = "E066"
 )
 
 var (
-	ErrAlreadyConnected  = errors.New("device already connected")
-	ErrNotLogin          = errors.New("device not login yet")
-	ErrRecipientNotFound = errors.New("recipient number not found")
+	ErrAlreadyConnected   = errors.New("device already connected")
+	ErrNotLogin           = errors.New("device not login yet")
+	ErrRecipientNotFound  = errors.New("recipient number not found")
+	ErrInvalidMedia       = errors.New("media must be given either as non empty base64 data or as url")
+	ErrMessageAndTemplate = errors.New("message and template cannot be given together")
 )
 
 var (
@@ -38,4 +58,145 @@ var (
 		Data:   map[string]any{},
 		Code:   ERecipientNotFound,
 	}
+	ErrRespRecipientSuppressed = &response.ErrorResponse{
+		E:      whatsapp.ErrRecipientSuppressed,
+		Status: http.StatusForbidden,
+		Data:   map[string]any{},
+		Code:   ERecipientSuppressed,
+	}
+	ErrRespInvalidMedia = &response.ErrorResponse{
+		E:      ErrInvalidMedia,
+		Status: http.StatusBadRequest,
+		Data:   map[string]any{},
+		Code:   EInvalidMedia,
+	}
+	ErrRespMessageAndTemplate = &response.ErrorResponse{
+		E:      ErrMessageAndTemplate,
+		Status: http.StatusBadRequest,
+		Data:   map[string]any{"fields": []string{"message", "template"}},
+		Code:   EMessageAndTemplate,
+	}
+	ErrRespInvalidMediaURL = &response.ErrorResponse{
+		E:      fetch.ErrInvalidURL,
+		Status: http.StatusBadRequest,
+		Data:   map[string]any{},
+		Code:   EInvalidMediaURL,
+	}
+	ErrRespMediaHostNotAllowed = &response.ErrorResponse{
+		E:      fetch.ErrHostNotAllowed,
+		Status: http.StatusForbidden,
+		Data:   map[string]any{},
+		Code:   EMediaHostNotAllowed,
+	}
+	ErrRespMediaTooLarge = &response.ErrorResponse{
+		E:      fetch.ErrTooLarge,
+		Status: http.StatusRequestEntityTooLarge,
+		Data:   map[string]any{},
+		Code:   EMediaTooLarge,
+	}
+	ErrRespNotGroupMember = &response.ErrorResponse{
+		E:      whatsapp.ErrNotGroupMember,
+		Status: http.StatusForbidden,
+		Data:   map[string]any{},
+		Code:   ENotGroupMember,
+	}
+	ErrRespGroupAdminsOnly = &response.ErrorResponse{
+		E:      whatsapp.ErrGroupAdminsOnly,
+		Status: http.StatusForbidden,
+		Data:   map[string]any{},
+		Code:   EGroupAdminsOnly,
+	}
+	ErrRespNotNewsletterAdmin = &response.ErrorResponse{
+		E:      whatsapp.ErrNotNewsletterAdmin,
+		Status: http.StatusForbidden,
+		Data:   map[string]any{},
+		Code:   ENotNewsletterAdmin,
+	}
+	ErrRespMentionAllNotGroup = &response.ErrorResponse{
+		E:      whatsapp.ErrMentionAllNotGroup,
+		Status: http.StatusBadRequest,
+		Data:   map[string]any{},
+		Code:   EMentionAllNotGroup,
+	}
+	ErrRespNotCommunity = &response.ErrorResponse{
+		E:      whatsapp.ErrNotCommunity,
+		Status: http.StatusBadRequest,
+		Data:   map[string]any{},
+		Code:   ENotCommunity,
+	}
+	ErrRespNoAnnouncementGroup = &response.ErrorResponse{
+		E:      whatsapp.ErrNoAnnouncementGroup,
+		Status: http.StatusNotFound,
+		Data:   map[string]any{},
+		Code:   ENoAnnouncementGroup,
+	}
 )
+
+// PhoneErrResp tells the caller which phone was rejected and why, through the error data.
+func PhoneErrResp(err error) error {
+	var perr *phone.Error
+	if !errors.As(err, &perr) {
+		return err
+	}
+	return &response.ErrorResponse{
+		E:      perr,
+		Status: http.StatusBadRequest,
+		Data: map[string]any{
+			"input":      perr.Input,
+			"reason":     perr.Reason,
+			"min_length": phone.MinLength,
+			"max_length": phone.MaxLength,
+		},
+		Code: EInvalidPhone,
+	}
+}
+
+// RecipientErrResp maps the errors of resolving and sending to a recipient.
+func RecipientErrResp(err error) error {
+	switch {
+	case errors.Is(err, phone.ErrInvalid):
+		return PhoneErrResp(err)
+	case errors.Is(err, whatsapp.ErrInvalidRecipient), errors.Is(err, whatsapp.ErrInvalidRecipientType):
+		return &response.ErrorResponse{
+			E:      err,
+			Status: http.StatusBadRequest,
+			Data:   map[string]any{"field": "recipient"},
+			Code:   EInvalidRecipient,
+		}
+	case errors.Is(err, whatsapp.ErrRecipientNotFound):
+		return ErrRespRecipientNotFound
+	case errors.Is(err, whatsapp.ErrRecipientSuppressed):
+		return ErrRespRecipientSuppressed
+	case errors.Is(err, whatsapp.ErrNotGroupMember):
+		return ErrRespNotGroupMember
+	case errors.Is(err, whatsapp.ErrGroupAdminsOnly):
+		return ErrRespGroupAdminsOnly
+	case errors.Is(err, whatsapp.ErrNotNewsletterAdmin):
+		return ErrRespNotNewsletterAdmin
+	case errors.Is(err, whatsapp.ErrNotCommunity):
+		return ErrRespNotCommunity
+	case errors.Is(err, whatsapp.ErrNoAnnouncementGroup):
+		return ErrRespNoAnnouncementGroup
+	}
+	return err
+}
+
+// MediaErrResp maps the errors of fetching media by url.
+func MediaErrResp(err error) error {
+	switch {
+	case errors.Is(err, fetch.ErrInvalidURL):
+		return ErrRespInvalidMediaURL
+	case errors.Is(err, fetch.ErrHostNotAllowed):
+		return ErrRespMediaHostNotAllowed
+	case errors.Is(err, fetch.ErrTooLarge):
+		return ErrRespMediaTooLarge
+	case errors.Is(err, fetch.ErrFetchFailed):
+		return &response.ErrorResponse{
+			E:      fetch.ErrFetchFailed,
+			Status: http.StatusBadGateway,
+			Data:   map[string]any{"reason": err.Error()},
+			Code:   EMediaFetchFailed,
+		}
+	}
+	return err
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/hrz8/whatsapp-api/internal/template"
+	"github.com/hrz8/whatsapp-api/pkg/avatar"
 	"github.com/hrz8/whatsapp-api/pkg/response"
 	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
 	"github.com/mdp/qrterminal/v3"
@@ -133,10 +135,74 @@ func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) (resp *response
 	return
 }
 
+// RecipientPayload is shared by the send endpoints. RecipientType is one of phone, group, community or newsletter
+// and inferred from the recipient when omitted; MentionAll notifies every member of a group recipient.
+// SimulateTyping shows the device typing for a while before the message is sent, see whatsapp.TypingDuration.
+type RecipientPayload struct {
+	Recipient      string                 `json:"recipient"`
+	RecipientType  whatsapp.RecipientType `json:"recipient_type"`
+	MentionAll     bool                   `json:"mention_all"`
+	SimulateTyping bool                   `json:"simulate_typing"`
+}
+
 type SendMessagePayload struct {
 	ClientDeviceID string `json:"client_device_id"`
-	Recipient      string `json:"recipient"`
-	Message        string `json:"message"`
+	RecipientPayload
+	Message   string            `json:"message"`
+	Template  string            `json:"template"`
+	Language  string            `json:"language"`
+	Variables map[string]string `json:"variables"`
+}
+
+// text resolves the message body, rendering the template when one is given instead of a plain message.
+func (h *Handler) text(p *SendMessagePayload) (string, error) {
+	if p.Template == "" {
+		return p.Message, nil
+	}
+	t, err := h.waCli.Templates().Get(p.Template, p.Language)
+	if err != nil {
+		return "", err
+	}
+	return t.Render(p.Variables)
+}
+
+// recipient resolves and checks the recipient before anything is uploaded.
+func (h *Handler) recipient(cli *whatsmeow.Client, clientDeviceID string, p *RecipientPayload) (*whatsapp.Recipient, error) {
+	rcpt, err := h.waCli.ResolveRecipient(cli, clientDeviceID, p.RecipientType, p.Recipient)
+	if err != nil {
+		return nil, RecipientErrResp(err)
+	}
+	if p.MentionAll && rcpt.Group == nil {
+		return nil, ErrRespMentionAllNotGroup
+	}
+	return rcpt, nil
+}
+
+// send delivers the message to the resolved recipient, mentioning the whole group and typing first when asked to.
+func (h *Handler) send(ctx context.Context, cli *whatsmeow.Client, clientDeviceID string, rcpt *whatsapp.Recipient, p *RecipientPayload, msg *waE2E.Message, extra ...whatsmeow.SendRequestExtra) (whatsmeow.SendResponse, error) {
+	if p.MentionAll {
+		msg = whatsapp.MentionAll(msg, rcpt.Mentions(cli))
+	}
+	if p.SimulateTyping && rcpt.Type != whatsapp.RecipientNewsletter {
+		// a suppressed recipient must not see the device typing either
+		suppressed, err := h.waCli.IsSuppressed(clientDeviceID, rcpt.JID)
+		if err != nil {
+			return whatsmeow.SendResponse{}, err
+		}
+		if suppressed {
+			return whatsmeow.SendResponse{}, ErrRespRecipientSuppressed
+		}
+		// a failed typing indicator is cosmetic, only a gone caller stops the message
+		_ = whatsapp.SimulateTyping(ctx, cli, rcpt.JID, msg)
+		if err := ctx.Err(); err != nil {
+			return whatsmeow.SendResponse{}, err
+		}
+	}
+	sent, err := h.waCli.SendMessage(ctx, cli, clientDeviceID, rcpt.JID, msg, extra...)
+	if err != nil {
+		return sent, RecipientErrResp(err)
+	}
+	return sent, nil
 }
 
 func (h *Handler) SendMessage(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
@@ -146,32 +212,158 @@ func (h *Handler) SendMessage(w http.ResponseWriter, r *http.Request) (resp *res
 		return nil, response.ErrRespServerUnexpected
 	}
 
-	cli := h.waCli.Get(p.ClientDeviceID)
-	if cli == nil {
-		return nil, ErrRespNotLogin
+	if p.Message != "" && p.Template != "" {
+		return nil, ErrRespMessageAndTemplate
 	}
-	if !cli.IsLoggedIn() {
+
+	cli, err := h.waCli.LoggedIn(p.ClientDeviceID)
+	if err != nil {
 		return nil, ErrRespNotLogin
 	}
 
-	jid, err := whatsapp.ParseJID(p.Recipient + "@s.whatsapp.net")
-	if errors.Is(err, ErrRecipientNotFound) {
-		return nil, ErrRespRecipientNotFound
+	rcpt, err := h.recipient(cli, p.ClientDeviceID, &p.RecipientPayload)
+	if err != nil {
+		return nil, err
 	}
 
-	msg := &waE2E.Message{Conversation: proto.String(p.Message)}
-	if whatsapp.IsOnWhatsapp(cli, jid.ToNonAD().String()) {
-		r, e := cli.SendMessage(context.Background(), jid.ToNonAD(), msg)
-		fmt.Println(r)
-		if e != nil {
-			fmt.Println(e)
-		}
+	text, err := h.text(&p)
+	if err != nil {
+		return nil, template.ErrResp(err)
+	}
+
+	msg := &waE2E.Message{Conversation: proto.String(text)}
+	sent, err := h.send(r.Context(), cli, p.ClientDeviceID, rcpt, &p.RecipientPayload, msg)
+	if err != nil {
+		return nil, err
 	}
 
 	resp = &response.Response{
 		Status:  http.StatusOK,
 		Message: "message sent",
-		Result:  map[string]any{"ok": true},
+		Result:  map[string]any{"ok": true, "message_id": sent.ID},
+		Error:   nil,
+	}
+	return
+}
+
+type SendMediaPayload struct {
+	ClientDeviceID string `json:"client_device_id"`
+	RecipientPayload
+	Data     string `json:"data"`
+	URL      string `json:"url"`
+	Mimetype string `json:"mimetype"`
+	Filename string `json:"filename"`
+	Caption  string `json:"caption"`
+}
+
+// DecodeData accepts plain base64 as well as a data url, returning the mimetype of the latter.
+func DecodeData(raw string) ([]byte, string, error) {
+	mimetype := ""
+	if meta, b64, ok := strings.Cut(raw, ";base64,"); ok && strings.HasPrefix(meta, "data:") {
+		raw = b64
+		mimetype = strings.TrimPrefix(meta, "data:")
+	}
+	data, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil || len(data) == 0 {
+		return nil, "", ErrInvalidMedia
+	}
+	return data, mimetype, nil
+}
+
+// PictureData reads a picture given either inline as data or by url and turns it into the square jpeg
+// whatsapp takes for profile and group pictures.
+func PictureData(ctx context.Context, waCli *whatsapp.Client, data string, url string) ([]byte, error) {
+	if (data == "") == (url == "") {
+		return nil, ErrInvalidMedia
+	}
+
+	var raw []byte
+	if url != "" {
+		file, err := waCli.FetchMedia(ctx, url)
+		if err != nil {
+			return nil, err
+		}
+		raw = file.Data
+	} else {
+		decoded, _, err := DecodeData(data)
+		if err != nil {
+			return nil, err
+		}
+		raw = decoded
+	}
+	return avatar.Square(raw)
+}
+
+// media uploads the payload media, given either inline as data or by url.
+// Newsletter media is uploaded unencrypted and needs the returned extra when sending.
+func (h *Handler) media(ctx context.Context, cli *whatsmeow.Client, rcpt *whatsapp.Recipient, p *SendMediaPayload) (*waE2E.Message, []whatsmeow.SendRequestExtra, error) {
+	if (p.Data == "") == (p.URL == "") {
+		return nil, nil, ErrRespInvalidMedia
+	}
+
+	var data []byte
+	mimetype, filename := p.Mimetype, p.Filename
+	if p.URL != "" {
+		file, err := h.waCli.FetchMedia(ctx, p.URL)
+		if err != nil {
+			return nil, nil, MediaErrResp(err)
+		}
+		data = file.Data
+		if mimetype == "" {
+			mimetype = file.ContentType
+		}
+		if filename == "" {
+			filename = file.Filename
+		}
+	} else {
+		decoded, dataMimetype, err := DecodeData(p.Data)
+		if err != nil {
+			return nil, nil, ErrRespInvalidMedia
+		}
+		data = decoded
+		if mimetype == "" {
+			mimetype = dataMimetype
+		}
+	}
+
+	if rcpt.Type == whatsapp.RecipientNewsletter {
+		msg, extra, err := h.waCli.UploadNewsletterMedia(ctx, cli, data, mimetype, filename, p.Caption)
+		return msg, []whatsmeow.SendRequestExtra{extra}, err
+	}
+	msg, err := h.waCli.UploadMedia(ctx, cli, data, mimetype, filename, p.Caption)
+	return msg, nil, err
+}
+
+func (h *Handler) SendMedia(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p SendMediaPayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	cli, err := h.waCli.LoggedIn(p.ClientDeviceID)
+	if err != nil {
+		return nil, ErrRespNotLogin
+	}
+
+	rcpt, err := h.recipient(cli, p.ClientDeviceID, &p.RecipientPayload)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, extra, err := h.media(r.Context(), cli, rcpt, &p)
+	if err != nil {
+		return nil, err
+	}
+	sent, err := h.send(r.Context(), cli, p.ClientDeviceID, rcpt, &p.RecipientPayload, msg, extra...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "media sent",
+		Result:  map[string]any{"ok": true, "message_id": sent.ID},
 		Error:   nil,
 	}
 	return
@@ -0,0 +1,28 @@
+package suppression
+
+import (
+	"net/http"
+
+	"github.com/hrz8/whatsapp-api/pkg/response"
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
+)
+
+const (
+	ESuppressionNotFound response.ErrCode = "E009"
+	EInvalidRecipient    response.ErrCode = "E010"
+)
+
+var (
+	ErrRespSuppressionNotFound = &response.ErrorResponse{
+		E:      whatsapp.ErrSuppressionNotFound,
+		Status: http.StatusNotFound,
+		Data:   map[string]any{},
+		Code:   ESuppressionNotFound,
+	}
+	ErrRespInvalidRecipient = &response.ErrorResponse{
+		E:      whatsapp.ErrRecipientNotFound,
+		Status: http.StatusBadRequest,
+		Data:   map[string]any{},
+		Code:   EInvalidRecipient,
+	}
+)
@@ -0,0 +1,106 @@
+package suppression
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/hrz8/whatsapp-api/internal/session"
+	"github.com/hrz8/whatsapp-api/pkg/phone"
+	"github.com/hrz8/whatsapp-api/pkg/response"
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
+)
+
+type Handler struct {
+	waCli *whatsapp.Client
+}
+
+func NewHandler(waCli *whatsapp.Client) *Handler {
+	return &Handler{waCli}
+}
+
+// SuppressionPayload targets a single device, or every device when client_device_id is empty.
+type SuppressionPayload struct {
+	ClientDeviceID string `json:"client_device_id"`
+	Recipient      string `json:"recipient"`
+	Reason         string `json:"reason"`
+}
+
+func (p *SuppressionPayload) jid(defaultCountry string) (string, error) {
+	rcpt, err := whatsapp.ParseRecipient(whatsapp.RecipientPhone, p.Recipient, defaultCountry)
+	if errors.Is(err, phone.ErrInvalid) {
+		return "", session.PhoneErrResp(err)
+	}
+	if err != nil {
+		return "", ErrRespInvalidRecipient
+	}
+	return rcpt.JID.String(), nil
+}
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	items, err := h.waCli.Suppressions().List(r.URL.Query().Get("client_device_id"))
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "suppressions found",
+		Result:  items,
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) Add(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p SuppressionPayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	jid, err := p.jid(h.waCli.DefaultCountry(p.ClientDeviceID))
+	if err != nil {
+		return nil, err
+	}
+	item, err := h.waCli.Suppressions().Add(p.ClientDeviceID, jid, p.Reason)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusCreated,
+		Message: "recipient suppressed",
+		Result:  item,
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) Remove(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p SuppressionPayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	jid, err := p.jid(h.waCli.DefaultCountry(p.ClientDeviceID))
+	if err != nil {
+		return nil, err
+	}
+	err = h.waCli.Suppressions().Remove(p.ClientDeviceID, jid)
+	if errors.Is(err, whatsapp.ErrSuppressionNotFound) {
+		return nil, ErrRespSuppressionNotFound
+	}
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "recipient unsuppressed",
+		Result:  map[string]any{"ok": true},
+		Error:   nil,
+	}
+	return
+}
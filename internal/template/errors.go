@@ -0,0 +1,65 @@
+package template
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/hrz8/whatsapp-api/pkg/response"
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
+)
+
+const (
+	ETemplateNotFound        response.ErrCode = "E004"
+	ETemplateAlreadyExist    response.ErrCode = "E005"
+	ETemplateInvalid         response.ErrCode = "E006"
+	ETemplateVariableMissing response.ErrCode = "E007"
+)
+
+var (
+	ErrRespTemplateNotFound = &response.ErrorResponse{
+		E:      whatsapp.ErrTemplateNotFound,
+		Status: http.StatusNotFound,
+		Data:   map[string]any{},
+		Code:   ETemplateNotFound,
+	}
+	ErrRespTemplateAlreadyExist = &response.ErrorResponse{
+		E:      whatsapp.ErrTemplateAlreadyExist,
+		Status: http.StatusConflict,
+		Data:   map[string]any{},
+		Code:   ETemplateAlreadyExist,
+	}
+	ErrRespTemplateInvalid = &response.ErrorResponse{
+		E:      whatsapp.ErrTemplateInvalid,
+		Status: http.StatusBadRequest,
+		Data:   map[string]any{},
+		Code:   ETemplateInvalid,
+	}
+)
+
+// ErrResp maps template errors coming from the whatsapp package into their error response.
+func ErrResp(err error) error {
+	var tplErr *whatsapp.TemplateError
+	switch {
+	case errors.As(err, &tplErr) && errors.Is(err, whatsapp.ErrTemplateVariableMissing):
+		return &response.ErrorResponse{
+			E:      whatsapp.ErrTemplateVariableMissing,
+			Status: http.StatusBadRequest,
+			Data:   map[string]any{"variables": tplErr.Names},
+			Code:   ETemplateVariableMissing,
+		}
+	case errors.As(err, &tplErr) && errors.Is(err, whatsapp.ErrTemplateInvalid):
+		return &response.ErrorResponse{
+			E:      whatsapp.ErrTemplateInvalid,
+			Status: http.StatusBadRequest,
+			Data:   map[string]any{"variables": tplErr.Names},
+			Code:   ETemplateInvalid,
+		}
+	case errors.Is(err, whatsapp.ErrTemplateInvalid):
+		return ErrRespTemplateInvalid
+	case errors.Is(err, whatsapp.ErrTemplateNotFound):
+		return ErrRespTemplateNotFound
+	case errors.Is(err, whatsapp.ErrTemplateAlreadyExist):
+		return ErrRespTemplateAlreadyExist
+	}
+	return response.ErrRespServerUnexpected
+}
@@ -0,0 +1,131 @@
+package template
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/hrz8/whatsapp-api/pkg/response"
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
+)
+
+type Handler struct {
+	waCli *whatsapp.Client
+}
+
+func NewHandler(waCli *whatsapp.Client) *Handler {
+	return &Handler{waCli}
+}
+
+type TemplatePayload struct {
+	Name      string                      `json:"name"`
+	Language  string                      `json:"language"`
+	Body      string                      `json:"body"`
+	Variables []whatsapp.TemplateVariable `json:"variables"`
+}
+
+func (p *TemplatePayload) toTemplate() *whatsapp.Template {
+	vars := p.Variables
+	if vars == nil {
+		vars = []whatsapp.TemplateVariable{}
+	}
+	return &whatsapp.Template{
+		Name:      p.Name,
+		Language:  p.Language,
+		Body:      p.Body,
+		Variables: vars,
+	}
+}
+
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p TemplatePayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+
+	t := p.toTemplate()
+	if err = t.Validate(); err != nil {
+		return nil, ErrResp(err)
+	}
+	if err = h.waCli.Templates().Create(t); err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusCreated,
+		Message: "template created",
+		Result:  t,
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	templates, err := h.waCli.Templates().List(r.URL.Query().Get("name"))
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "templates found",
+		Result:  templates,
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	t, err := h.waCli.Templates().Get(r.PathValue("name"), r.URL.Query().Get("language"))
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "template found",
+		Result:  t,
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	var p TemplatePayload
+	err = json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, response.ErrRespServerUnexpected
+	}
+	p.Name = r.PathValue("name")
+
+	t := p.toTemplate()
+	if err = t.Validate(); err != nil {
+		return nil, ErrResp(err)
+	}
+	if err = h.waCli.Templates().Update(t); err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "template updated",
+		Result:  t,
+		Error:   nil,
+	}
+	return
+}
+
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) (resp *response.Response, err error) {
+	err = h.waCli.Templates().Delete(r.PathValue("name"), r.URL.Query().Get("language"))
+	if err != nil {
+		return nil, ErrResp(err)
+	}
+
+	resp = &response.Response{
+		Status:  http.StatusOK,
+		Message: "template deleted",
+		Result:  map[string]any{"ok": true},
+		Error:   nil,
+	}
+	return
+}
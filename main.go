@@ -10,14 +10,25 @@ import (
 	"strings"
 	"syscall"
 
+	"github.com/hrz8/whatsapp-api/internal/chat"
+	"github.com/hrz8/whatsapp-api/internal/contact"
+	"github.com/hrz8/whatsapp-api/internal/device"
+	"github.com/hrz8/whatsapp-api/internal/event"
+	"github.com/hrz8/whatsapp-api/internal/group"
+	"github.com/hrz8/whatsapp-api/internal/media"
+	"github.com/hrz8/whatsapp-api/internal/rule"
+	"github.com/hrz8/whatsapp-api/internal/schedule"
 	"github.com/hrz8/whatsapp-api/internal/session"
+	"github.com/hrz8/whatsapp-api/internal/suppression"
+	"github.com/hrz8/whatsapp-api/internal/template"
+	"github.com/hrz8/whatsapp-api/pkg/autoreply"
+	"github.com/hrz8/whatsapp-api/pkg/blob"
+	"github.com/hrz8/whatsapp-api/pkg/fetch"
 	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jackc/pgx/v5/stdlib"
 	"go.mau.fi/whatsmeow"
-	"go.mau.fi/whatsmeow/proto/waE2E"
 	"go.mau.fi/whatsmeow/types/events"
-	"google.golang.org/protobuf/proto"
 )
 
 var (
@@ -39,16 +50,36 @@ func eventHandler(cli *whatsmeow.Client, _ string) whatsmeow.EventHandler {
 				metaParts = append(metaParts, "view once")
 			}
 			fmt.Printf("Received message %s from %s (%s): %+v\n", v.Info.ID, v.Info.SourceString(), strings.Join(metaParts, ", "), v.Message)
+		}
+	}
+}
 
-			if !v.Info.IsFromMe &&
-				!whatsapp.IsGroupJid(v.Info.Chat.String()) &&
-				!strings.Contains(v.Info.SourceString(), "broadcast") &&
-				whatsapp.IsOnWhatsapp(cli, v.Info.Sender.ToNonAD().String()) {
-				msg := &waE2E.Message{Conversation: proto.String("Sorry, I'm quite busy for now...")}
-				_, _ = cli.SendMessage(context.Background(), v.Info.Sender.ToNonAD(), msg)
-			}
+func newBlobStore() (blob.Store, error) {
+	if AppMediaBackend != "s3" {
+		return blob.NewFSStore(AppMediaDir)
+	}
+
+	s3, err := blob.NewS3Store(blob.S3Config{
+		Endpoint:  AppS3Endpoint,
+		Region:    AppS3Region,
+		Bucket:    AppS3Bucket,
+		Prefix:    AppS3Prefix,
+		AccessKey: AppS3AccessKey,
+		SecretKey: AppS3SecretKey,
+		PathStyle: AppS3PathStyle,
+	})
+	if errors.Is(err, blob.ErrMissingS3Credentials) {
+		return nil, fmt.Errorf("media backend s3 needs AppS3AccessKey and AppS3SecretKey: %w", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if AppMediaRetentionDays > 0 {
+		if err := s3.SetRetention(context.Background(), AppMediaRetentionDays); err != nil {
+			fmt.Println("cannot set media retention:", err)
 		}
 	}
+	return s3, nil
 }
 
 func main() {
@@ -59,11 +90,32 @@ func main() {
 
 	db := stdlib.OpenDBFromPool(conn)
 
+	blobs, err := newBlobStore()
+	if err != nil {
+		panic(err)
+	}
+
 	waCli := whatsapp.NewClient(
 		db,
 		whatsapp.WithOsInfo(AppOs, AppVersions),
+		whatsapp.WithOptOutKeywords(AppOptOutKeywords...),
+		whatsapp.WithHistorySync(AppHistorySync, AppHistorySyncMaxAge),
+		whatsapp.WithMediaStore(blobs, whatsapp.MediaDownloadConfig{Types: AppMediaTypes, MaxSize: AppMediaMaxSize}),
+		whatsapp.WithMediaFetcher(fetch.New(fetch.Config{
+			MaxSize:      AppMediaMaxSize,
+			Timeout:      AppMediaFetchTimeout,
+			AllowedHosts: AppMediaFetchHosts,
+		})),
+		whatsapp.WithContactCache(whatsapp.ContactCacheConfig{
+			TTL:         AppContactCacheTTL,
+			NegativeTTL: AppContactCacheNegativeTTL,
+			Persist:     AppContactCachePersist,
+		}),
+		whatsapp.WithDefaultCountry(AppDefaultCountryCode),
 		whatsapp.WithEventHandler(eventHandler),
 	)
+	engine := autoreply.NewEngine(waCli, db)
+	waCli.AddEventHandler(engine.EventHandler)
 	waCli.Upgrade()
 	defer func() {
 		waCli.Backup()
@@ -73,10 +125,112 @@ func main() {
 	// server
 	mux := http.NewServeMux()
 	sess := session.NewHandler(waCli)
+	tpl := template.NewHandler(waCli)
+	sup := suppression.NewHandler(waCli)
+	rl := rule.NewHandler(engine)
+	sch := schedule.NewHandler(engine)
+	cht := chat.NewHandler(waCli)
+	med := media.NewHandler(waCli)
+	grp := group.NewHandler(waCli)
+	evt := event.NewHandler(waCli)
+	cnt := contact.NewHandler(waCli)
+	dev := device.NewHandler(waCli)
 
 	mux.Handle("POST /qr", Handler(sess.GenQR))
 	mux.Handle("POST /logout", Handler(sess.Logout))
 	mux.Handle("POST /send-message", Handler(sess.SendMessage))
+	mux.Handle("POST /send-media", Handler(sess.SendMedia))
+
+	mux.Handle("POST /templates", Handler(tpl.Create))
+	mux.Handle("GET /templates", Handler(tpl.List))
+	mux.Handle("GET /templates/{name}", Handler(tpl.Get))
+	mux.Handle("PUT /templates/{name}", Handler(tpl.Update))
+	mux.Handle("DELETE /templates/{name}", Handler(tpl.Delete))
+
+	mux.Handle("GET /suppressions", Handler(sup.List))
+	mux.Handle("POST /suppressions", Handler(sup.Add))
+	mux.Handle("DELETE /suppressions", Handler(sup.Remove))
+
+	mux.Handle("GET /rules", Handler(rl.List))
+	mux.Handle("POST /rules", Handler(rl.Create))
+	mux.Handle("GET /rules/{id}", Handler(rl.Get))
+	mux.Handle("PUT /rules/{id}", Handler(rl.Update))
+	mux.Handle("DELETE /rules/{id}", Handler(rl.Delete))
+
+	mux.Handle("GET /devices/{client_device_id}/settings", Handler(dev.Settings))
+	mux.Handle("PUT /devices/{client_device_id}/settings", Handler(dev.SaveSettings))
+	mux.Handle("GET /devices/{client_device_id}/privacy", Handler(dev.Privacy))
+	mux.Handle("PUT /devices/{client_device_id}/privacy", Handler(dev.SetPrivacy))
+	mux.Handle("PUT /devices/{client_device_id}/presence", Handler(dev.SetPresence))
+	mux.Handle("GET /devices/{client_device_id}/profile", Handler(dev.Profile))
+	mux.Handle("PUT /devices/{client_device_id}/profile", Handler(dev.SetProfile))
+	mux.Handle("PUT /devices/{client_device_id}/profile/picture", Handler(dev.SetPicture))
+	mux.Handle("DELETE /devices/{client_device_id}/profile/picture", Handler(dev.RemovePicture))
+
+	mux.Handle("GET /devices/{client_device_id}/schedule", Handler(sch.Get))
+	mux.Handle("PUT /devices/{client_device_id}/schedule", Handler(sch.Save))
+	mux.Handle("DELETE /devices/{client_device_id}/schedule", Handler(sch.Delete))
+	mux.Handle("GET /devices/{client_device_id}/open", Handler(sch.IsOpen))
+
+	mux.Handle("GET /chats", Handler(cht.List))
+	mux.Handle("GET /chats/{jid}/messages", Handler(cht.Messages))
+	mux.Handle("POST /chats/{jid}/presence", Handler(cht.Presence))
+	mux.Handle("POST /chats/{jid}/read", Handler(cht.Read))
+	mux.Handle("POST /chats/{jid}/archive", Handler(cht.Archive))
+	mux.Handle("POST /chats/{jid}/unarchive", Handler(cht.Unarchive))
+	mux.Handle("POST /chats/{jid}/pin", Handler(cht.Pin))
+	mux.Handle("POST /chats/{jid}/unpin", Handler(cht.Unpin))
+	mux.Handle("POST /chats/{jid}/mute", Handler(cht.Mute))
+	mux.Handle("POST /chats/{jid}/unmute", Handler(cht.Unmute))
+	mux.Handle("POST /chats/{jid}/unread", Handler(cht.Unread))
+	mux.Handle("POST /chats/{jid}/clear", Handler(cht.Clear))
+	mux.Handle("DELETE /chats/{jid}", Handler(cht.Delete))
+	mux.Handle("GET /chats/{jid}/messages/{message_id}/receipts", Handler(cht.Receipts))
+	mux.Handle("GET /devices/{client_device_id}/history-sync", Handler(cht.HistorySync))
+	mux.Handle("GET /messages/search", Handler(cht.Search))
+
+	mux.Handle("GET /media/{id}", RawHandler(med.Download))
+	mux.Handle("GET /media/{id}/url", Handler(med.URL))
+
+	mux.Handle("POST /groups", Handler(grp.Create))
+	mux.Handle("GET /groups", Handler(grp.List))
+	mux.Handle("GET /groups/{jid}", Handler(grp.Get))
+	mux.Handle("POST /groups/{jid}/participants", Handler(grp.UpdateParticipants))
+	mux.Handle("PUT /groups/{jid}/subject", Handler(grp.SetSubject))
+	mux.Handle("PUT /groups/{jid}/description", Handler(grp.SetDescription))
+	mux.Handle("PUT /groups/{jid}/picture", Handler(grp.SetPicture))
+	mux.Handle("DELETE /groups/{jid}/picture", Handler(grp.RemovePicture))
+	mux.Handle("PUT /groups/{jid}/settings", Handler(grp.SetSettings))
+	mux.Handle("POST /groups/{jid}/leave", Handler(grp.Leave))
+	mux.Handle("GET /groups/{jid}/invite-link", Handler(grp.InviteLink))
+	mux.Handle("POST /groups/{jid}/invite-link/reset", Handler(grp.ResetInviteLink))
+	mux.Handle("GET /groups/{jid}/requests", Handler(grp.Requests))
+	mux.Handle("POST /groups/{jid}/requests", Handler(grp.UpdateRequests))
+	mux.Handle("POST /communities", Handler(grp.CreateCommunity))
+	mux.Handle("GET /communities", Handler(grp.ListCommunities))
+	mux.Handle("GET /communities/{jid}/groups", Handler(grp.SubGroups))
+	mux.Handle("POST /communities/{jid}/groups", Handler(grp.LinkGroup))
+	mux.Handle("DELETE /communities/{jid}/groups/{group}", Handler(grp.UnlinkGroup))
+	mux.Handle("GET /group-invites/{code}", Handler(grp.PreviewInvite))
+	mux.Handle("POST /group-invites/{code}/join", Handler(grp.JoinInvite))
+
+	mux.Handle("GET /events", RawHandler(evt.Stream))
+	mux.Handle("POST /events/ack", Handler(evt.Ack))
+
+	mux.Handle("GET /contacts", Handler(cnt.List))
+	mux.Handle("GET /contacts/{jid}", Handler(cnt.Info))
+	mux.Handle("GET /contacts/{jid}/picture", Handler(cnt.Picture))
+	mux.Handle("GET /contacts/{jid}/business", Handler(cnt.Business))
+	mux.Handle("GET /contacts/presence", Handler(cnt.Presences))
+	mux.Handle("GET /contacts/{jid}/presence", Handler(cnt.Presence))
+	mux.Handle("POST /contacts/{jid}/presence", Handler(cnt.SubscribePresence))
+	mux.Handle("DELETE /contacts/{jid}/presence", Handler(cnt.UnsubscribePresence))
+	mux.Handle("POST /contacts/{jid}/block", Handler(cnt.Block))
+	mux.Handle("POST /contacts/{jid}/unblock", Handler(cnt.Unblock))
+	mux.Handle("GET /blocklist", Handler(cnt.Blocklist))
+	mux.Handle("POST /contacts/check", Handler(cnt.Check))
+	mux.Handle("GET /contacts/cache", Handler(cnt.CacheStats))
+	mux.Handle("DELETE /contacts/cache", Handler(cnt.InvalidateCache))
 
 	server := http.Server{
 		Addr:    fmt.Sprintf(":%s", AppPort),
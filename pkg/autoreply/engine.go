@@ -0,0 +1,196 @@
+package autoreply
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types/events"
+	waLog "go.mau.fi/whatsmeow/util/log"
+	"google.golang.org/protobuf/proto"
+)
+
+// Engine answers incoming messages with the first matching rule of the receiving device.
+type Engine struct {
+	mut      sync.Mutex
+	cooldown map[string]time.Time
+
+	waCli     *whatsapp.Client
+	rules     *RuleRepo
+	schedules *ScheduleRepo
+	log       waLog.Logger
+}
+
+func NewEngine(waCli *whatsapp.Client, db *sql.DB) *Engine {
+	return &Engine{
+		cooldown: make(map[string]time.Time),
+
+		waCli:     waCli,
+		rules:     &RuleRepo{db},
+		schedules: &ScheduleRepo{db},
+		log:       waLog.Stdout("AutoReply", whatsapp.LogLevel, true),
+	}
+}
+
+func (e *Engine) Rules() *RuleRepo {
+	return e.rules
+}
+
+func (e *Engine) Schedules() *ScheduleRepo {
+	return e.schedules
+}
+
+// IsOpen reports whether the device is within its business hours, devices without a schedule are always open.
+func (e *Engine) IsOpen(clientDeviceID string, now time.Time) (bool, error) {
+	schedule, err := e.schedules.Get(clientDeviceID)
+	if errors.Is(err, ErrScheduleNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return schedule.IsOpen(now), nil
+}
+
+// EventHandler is meant to be registered through whatsapp.Client.AddEventHandler.
+func (e *Engine) EventHandler(cli *whatsmeow.Client, clientDeviceID string) whatsmeow.EventHandler {
+	return func(evt any) {
+		switch v := evt.(type) {
+		case *events.Message:
+			e.handleMessage(cli, clientDeviceID, v)
+		}
+	}
+}
+
+func (e *Engine) handleMessage(cli *whatsmeow.Client, clientDeviceID string, evt *events.Message) {
+	if evt.Info.IsFromMe || strings.Contains(evt.Info.SourceString(), "broadcast") {
+		return
+	}
+	// a bot command already got its answer
+	if e.waCli.Handled(clientDeviceID, evt) {
+		return
+	}
+
+	rules, err := e.rules.ListEnabled(clientDeviceID)
+	if err != nil {
+		e.log.Errorf("cannot load rules for client id: %v, %v", clientDeviceID, err)
+		return
+	}
+
+	text := whatsapp.MessageText(evt.Message)
+	now := time.Now()
+	for _, rule := range rules {
+		if !rule.Matches(evt, text, now) {
+			continue
+		}
+		key := fmt.Sprintf("%d|%s|%s", rule.ID, evt.Info.Chat, evt.Info.Sender.ToNonAD())
+		if !e.acquire(key, time.Duration(rule.CooldownSeconds)*time.Second, now) {
+			e.log.Debugf("rule %v is cooling down for %v", rule.ID, evt.Info.Sender)
+			return
+		}
+		if err := e.reply(cli, clientDeviceID, rule.Action, evt); err != nil {
+			e.release(key)
+			e.log.Errorf("cannot reply with rule %v for client id: %v, %v", rule.ID, clientDeviceID, err)
+		}
+		return
+	}
+
+	if !evt.Info.IsGroup {
+		e.replyAway(cli, clientDeviceID, evt, now)
+	}
+}
+
+// replyAway sends the away message of the device schedule when no rule answered a private chat outside business hours.
+func (e *Engine) replyAway(cli *whatsmeow.Client, clientDeviceID string, evt *events.Message, now time.Time) {
+	schedule, err := e.schedules.Get(clientDeviceID)
+	if errors.Is(err, ErrScheduleNotFound) {
+		return
+	}
+	if err != nil {
+		e.log.Errorf("cannot load schedule for client id: %v, %v", clientDeviceID, err)
+		return
+	}
+	if schedule.AwayMessage == "" || schedule.IsOpen(now) {
+		return
+	}
+
+	key := fmt.Sprintf("away|%s|%s", clientDeviceID, evt.Info.Sender.ToNonAD())
+	if !e.acquire(key, time.Duration(schedule.AwayCooldownSeconds)*time.Second, now) {
+		return
+	}
+	action := Action{Type: ActionText, Text: schedule.AwayMessage}
+	if err := e.reply(cli, clientDeviceID, action, evt); err != nil {
+		e.release(key)
+		e.log.Errorf("cannot send away message for client id: %v, %v", clientDeviceID, err)
+	}
+}
+
+// acquire reports whether the rule may fire for the contact, and starts its cooldown if so.
+func (e *Engine) acquire(key string, cooldown time.Duration, now time.Time) bool {
+	e.mut.Lock()
+	defer e.mut.Unlock()
+
+	if until, ok := e.cooldown[key]; ok && now.Before(until) {
+		return false
+	}
+	if cooldown > 0 {
+		e.cooldown[key] = now.Add(cooldown)
+	}
+	if len(e.cooldown) > 1024 {
+		for k, until := range e.cooldown {
+			if now.After(until) {
+				delete(e.cooldown, k)
+			}
+		}
+	}
+	return true
+}
+
+// release ends the cooldown again when the reply could not be sent, so the next message is answered.
+func (e *Engine) release(key string) {
+	e.mut.Lock()
+	defer e.mut.Unlock()
+	delete(e.cooldown, key)
+}
+
+func (e *Engine) reply(cli *whatsmeow.Client, clientDeviceID string, action Action, evt *events.Message) error {
+	ctx := context.Background()
+	msg, err := e.buildMessage(ctx, cli, action, evt)
+	if err != nil {
+		return err
+	}
+	_, err = e.waCli.SendMessage(ctx, cli, clientDeviceID, evt.Info.Chat, msg)
+	return err
+}
+
+func (e *Engine) buildMessage(ctx context.Context, cli *whatsmeow.Client, action Action, evt *events.Message) (*waE2E.Message, error) {
+	switch action.Type {
+	case ActionTemplate:
+		t, err := e.waCli.Templates().Get(action.Template, action.Language)
+		if err != nil {
+			return nil, err
+		}
+		vars := map[string]string{
+			"sender_name":  evt.Info.PushName,
+			"sender_phone": evt.Info.Sender.User,
+		}
+		for k, v := range action.Variables {
+			vars[k] = v
+		}
+		text, err := t.Render(vars)
+		if err != nil {
+			return nil, err
+		}
+		return &waE2E.Message{Conversation: proto.String(text)}, nil
+	case ActionMedia:
+		return e.waCli.UploadMediaURL(ctx, cli, action.MediaURL, "", action.Caption)
+	}
+	return &waE2E.Message{Conversation: proto.String(action.Text)}, nil
+}
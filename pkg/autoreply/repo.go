@@ -0,0 +1,160 @@
+package autoreply
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+)
+
+type RuleRepo struct {
+	db *sql.DB
+}
+
+func (r *RuleRepo) scan(row interface{ Scan(...any) error }) (*Rule, error) {
+	var i Rule
+	var match, action []byte
+	err := row.Scan(
+		&i.ID,
+		&i.ClientDeviceID,
+		&i.Name,
+		&i.Priority,
+		&i.Enabled,
+		&match,
+		&action,
+		&i.CooldownSeconds,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(match, &i.Match); err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(action, &i.Action)
+	return &i, err
+}
+
+func (r *RuleRepo) list(query string, args ...any) ([]*Rule, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := make([]*Rule, 0)
+	for rows.Next() {
+		rule, err := r.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+// List returns the rules of a device, highest priority first.
+func (r *RuleRepo) List(clientDeviceID string) ([]*Rule, error) {
+	return r.list(`SELECT id, client_device_id, name, priority, enabled, match, action, cooldown_seconds, created_at, updated_at
+		FROM whatsmeow_extended_autoreply_rule
+		WHERE client_device_id = $1
+		ORDER BY priority DESC, id`,
+		clientDeviceID,
+	)
+}
+
+func (r *RuleRepo) ListEnabled(clientDeviceID string) ([]*Rule, error) {
+	return r.list(`SELECT id, client_device_id, name, priority, enabled, match, action, cooldown_seconds, created_at, updated_at
+		FROM whatsmeow_extended_autoreply_rule
+		WHERE client_device_id = $1 AND enabled
+		ORDER BY priority DESC, id`,
+		clientDeviceID,
+	)
+}
+
+func (r *RuleRepo) Get(id int) (*Rule, error) {
+	row := r.db.QueryRow(`SELECT id, client_device_id, name, priority, enabled, match, action, cooldown_seconds, created_at, updated_at
+		FROM whatsmeow_extended_autoreply_rule
+		WHERE id = $1`,
+		id,
+	)
+	rule, err := r.scan(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRuleNotFound
+	}
+	return rule, err
+}
+
+func (r *RuleRepo) Create(rule *Rule) error {
+	match, err := json.Marshal(rule.Match)
+	if err != nil {
+		return err
+	}
+	action, err := json.Marshal(rule.Action)
+	if err != nil {
+		return err
+	}
+	row := r.db.QueryRow(`INSERT INTO
+		whatsmeow_extended_autoreply_rule (
+			client_device_id,
+			name,
+			priority,
+			enabled,
+			match,
+			action,
+			cooldown_seconds
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, created_at, updated_at`,
+		rule.ClientDeviceID,
+		rule.Name,
+		rule.Priority,
+		rule.Enabled,
+		string(match),
+		string(action),
+		rule.CooldownSeconds,
+	)
+	return row.Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt)
+}
+
+func (r *RuleRepo) Update(rule *Rule) error {
+	match, err := json.Marshal(rule.Match)
+	if err != nil {
+		return err
+	}
+	action, err := json.Marshal(rule.Action)
+	if err != nil {
+		return err
+	}
+	row := r.db.QueryRow(`UPDATE whatsmeow_extended_autoreply_rule
+		SET client_device_id = $2, name = $3, priority = $4, enabled = $5, match = $6, action = $7, cooldown_seconds = $8, updated_at = now()
+		WHERE id = $1
+		RETURNING created_at, updated_at`,
+		rule.ID,
+		rule.ClientDeviceID,
+		rule.Name,
+		rule.Priority,
+		rule.Enabled,
+		string(match),
+		string(action),
+		rule.CooldownSeconds,
+	)
+	err = row.Scan(&rule.CreatedAt, &rule.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrRuleNotFound
+	}
+	forgetRegex(rule.ID)
+	return err
+}
+
+func (r *RuleRepo) Delete(id int) error {
+	res, err := r.db.Exec("DELETE FROM whatsmeow_extended_autoreply_rule WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	forgetRegex(id)
+	n, err := res.RowsAffected()
+	if err == nil && n == 0 {
+		return ErrRuleNotFound
+	}
+	return err
+}
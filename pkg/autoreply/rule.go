@@ -0,0 +1,284 @@
+package autoreply
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+type ChatType string
+
+const (
+	ChatTypeAny     ChatType = ""
+	ChatTypePrivate ChatType = "private"
+	ChatTypeGroup   ChatType = "group"
+)
+
+type ActionType string
+
+const (
+	ActionText     ActionType = "text"
+	ActionTemplate ActionType = "template"
+	ActionMedia    ActionType = "media"
+)
+
+var (
+	ErrRuleNotFound = errors.New("auto reply rule not found")
+	ErrRuleInvalid  = errors.New("auto reply rule is invalid")
+)
+
+// TimeWindow limits a rule to a daily time range, e.g. 09:00-17:00 on weekdays.
+// An end before the start wraps around midnight.
+type TimeWindow struct {
+	Start    string         `json:"start"`
+	End      string         `json:"end"`
+	Days     []time.Weekday `json:"days"`
+	Timezone string         `json:"timezone"`
+}
+
+// Match holds the conditions of a rule, every condition that is set has to match.
+type Match struct {
+	Keywords   []string    `json:"keywords"`
+	Regex      string      `json:"regex"`
+	Senders    []string    `json:"senders"`
+	ChatType   ChatType    `json:"chat_type"`
+	TimeWindow *TimeWindow `json:"time_window"`
+}
+
+type Action struct {
+	Type      ActionType        `json:"type"`
+	Text      string            `json:"text,omitempty"`
+	Template  string            `json:"template,omitempty"`
+	Language  string            `json:"language,omitempty"`
+	Variables map[string]string `json:"variables,omitempty"`
+	MediaURL  string            `json:"media_url,omitempty"`
+	Caption   string            `json:"caption,omitempty"`
+}
+
+type Rule struct {
+	ID              int       `json:"id"`
+	ClientDeviceID  string    `json:"client_device_id"`
+	Name            string    `json:"name"`
+	Priority        int       `json:"priority"`
+	Enabled         bool      `json:"enabled"`
+	Match           Match     `json:"match"`
+	Action          Action    `json:"action"`
+	CooldownSeconds int       `json:"cooldown_seconds"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// ValidationError describes which field caused a rule or schedule to be rejected.
+type ValidationError struct {
+	E      error
+	Field  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s %s", e.E.Error(), e.Field, e.Reason)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.E
+}
+
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func (w *TimeWindow) contains(now time.Time) bool {
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		return false
+	}
+	now = now.In(loc)
+	start, _ := parseClock(w.Start)
+	end, _ := parseClock(w.End)
+	minute := now.Hour()*60 + now.Minute()
+
+	day := now.Weekday()
+	// a window wrapping midnight belongs to the day it started
+	if end < start && minute < end {
+		day = (day + 6) % 7
+	}
+	if len(w.Days) > 0 {
+		found := false
+		for _, d := range w.Days {
+			if d == day {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if start <= end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+func (r *Rule) Validate() error {
+	if r.ClientDeviceID == "" {
+		return &ValidationError{ErrRuleInvalid, "client_device_id", "is required"}
+	}
+	if r.Name == "" {
+		return &ValidationError{ErrRuleInvalid, "name", "is required"}
+	}
+	if r.CooldownSeconds < 0 {
+		return &ValidationError{ErrRuleInvalid, "cooldown_seconds", "must not be negative"}
+	}
+	if r.Match.Regex != "" {
+		if _, err := regexp.Compile(r.Match.Regex); err != nil {
+			return &ValidationError{ErrRuleInvalid, "match.regex", "does not compile"}
+		}
+	}
+	switch r.Match.ChatType {
+	case ChatTypeAny, ChatTypePrivate, ChatTypeGroup:
+	default:
+		return &ValidationError{ErrRuleInvalid, "match.chat_type", "must be private or group"}
+	}
+	if w := r.Match.TimeWindow; w != nil {
+		if _, err := parseClock(w.Start); err != nil {
+			return &ValidationError{ErrRuleInvalid, "match.time_window.start", "must be formatted as HH:MM"}
+		}
+		if _, err := parseClock(w.End); err != nil {
+			return &ValidationError{ErrRuleInvalid, "match.time_window.end", "must be formatted as HH:MM"}
+		}
+		if _, err := time.LoadLocation(w.Timezone); err != nil {
+			return &ValidationError{ErrRuleInvalid, "match.time_window.timezone", "is unknown"}
+		}
+	}
+
+	switch r.Action.Type {
+	case ActionText:
+		if r.Action.Text == "" {
+			return &ValidationError{ErrRuleInvalid, "action.text", "is required"}
+		}
+	case ActionTemplate:
+		if r.Action.Template == "" {
+			return &ValidationError{ErrRuleInvalid, "action.template", "is required"}
+		}
+	case ActionMedia:
+		if r.Action.MediaURL == "" {
+			return &ValidationError{ErrRuleInvalid, "action.media_url", "is required"}
+		}
+	default:
+		return &ValidationError{ErrRuleInvalid, "action.type", "must be text, template or media"}
+	}
+	return nil
+}
+
+func matchKeywords(keywords []string, text string) bool {
+	text = strings.ToLower(text)
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	for _, keyword := range keywords {
+		keyword = strings.ToLower(strings.TrimSpace(keyword))
+		if keyword == "" {
+			continue
+		}
+		// phrases are matched as a substring, single words as a whole word
+		if strings.ContainsRune(keyword, ' ') {
+			if strings.Contains(text, keyword) {
+				return true
+			}
+			continue
+		}
+		for _, word := range words {
+			if word == keyword {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchSender(senders []string, sender types.JID) bool {
+	for _, s := range senders {
+		s = strings.TrimPrefix(s, "+")
+		if s == sender.User || s == sender.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleRegex is the compiled pattern of a rule, kept along with the pattern it was compiled from.
+type ruleRegex struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+// regexes keeps the compiled pattern of every rule by id, since rules are loaded again for every incoming
+// message. A changed pattern replaces the entry, updated and deleted rules drop theirs through forgetRegex.
+var regexes = struct {
+	mut   sync.Mutex
+	rules map[int]*ruleRegex
+}{rules: make(map[int]*ruleRegex)}
+
+func (r *Rule) regex() (*regexp.Regexp, error) {
+	regexes.mut.Lock()
+	defer regexes.mut.Unlock()
+	if cached, ok := regexes.rules[r.ID]; ok && cached.pattern == r.Match.Regex {
+		return cached.re, nil
+	}
+	re, err := regexp.Compile(r.Match.Regex)
+	if err != nil {
+		return nil, err
+	}
+	regexes.rules[r.ID] = &ruleRegex{r.Match.Regex, re}
+	return re, nil
+}
+
+func forgetRegex(id int) {
+	regexes.mut.Lock()
+	defer regexes.mut.Unlock()
+	delete(regexes.rules, id)
+}
+
+// Matches reports whether the message satisfies every condition of the rule.
+func (r *Rule) Matches(evt *events.Message, text string, now time.Time) bool {
+	m := r.Match
+	switch m.ChatType {
+	case ChatTypePrivate:
+		if evt.Info.IsGroup {
+			return false
+		}
+	case ChatTypeGroup:
+		if !evt.Info.IsGroup {
+			return false
+		}
+	}
+	if len(m.Senders) > 0 && !matchSender(m.Senders, evt.Info.Sender.ToNonAD()) {
+		return false
+	}
+	if len(m.Keywords) > 0 && !matchKeywords(m.Keywords, text) {
+		return false
+	}
+	if m.Regex != "" {
+		re, err := r.regex()
+		if err != nil || !re.MatchString(text) {
+			return false
+		}
+	}
+	if m.TimeWindow != nil && !m.TimeWindow.contains(now) {
+		return false
+	}
+	return true
+}
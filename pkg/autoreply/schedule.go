@@ -0,0 +1,214 @@
+package autoreply
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+var (
+	ErrScheduleNotFound = errors.New("business hours schedule not found")
+	ErrScheduleInvalid  = errors.New("business hours schedule is invalid")
+)
+
+// Interval is an opening range within a day, an end before the start closes on the next day.
+type Interval struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+type Holiday struct {
+	Date string `json:"date"`
+	Name string `json:"name"`
+}
+
+// Schedule holds the weekly opening hours of a device keyed by lowercase weekday name (e.g. "monday").
+// Days without intervals and holidays are closed the whole day.
+type Schedule struct {
+	ClientDeviceID      string                `json:"client_device_id"`
+	Enabled             bool                  `json:"enabled"`
+	Timezone            string                `json:"timezone"`
+	Weekly              map[string][]Interval `json:"weekly"`
+	Holidays            []Holiday             `json:"holidays"`
+	AwayMessage         string                `json:"away_message"`
+	AwayCooldownSeconds int                   `json:"away_cooldown_seconds"`
+	UpdatedAt           time.Time             `json:"updated_at"`
+}
+
+func weekdayName(d time.Weekday) string {
+	return strings.ToLower(d.String())
+}
+
+func (s *Schedule) Validate() error {
+	if s.Timezone == "" {
+		s.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(s.Timezone); err != nil {
+		return &ValidationError{ErrScheduleInvalid, "timezone", "is unknown"}
+	}
+	if s.AwayCooldownSeconds < 0 {
+		return &ValidationError{ErrScheduleInvalid, "away_cooldown_seconds", "must not be negative"}
+	}
+
+	days := make(map[string]bool)
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		days[weekdayName(d)] = true
+	}
+	for day, intervals := range s.Weekly {
+		if !days[day] {
+			return &ValidationError{ErrScheduleInvalid, "weekly." + day, "is not a weekday"}
+		}
+		for _, i := range intervals {
+			if _, err := parseClock(i.Start); err != nil {
+				return &ValidationError{ErrScheduleInvalid, "weekly." + day + ".start", "must be formatted as HH:MM"}
+			}
+			if _, err := parseClock(i.End); err != nil {
+				return &ValidationError{ErrScheduleInvalid, "weekly." + day + ".end", "must be formatted as HH:MM"}
+			}
+		}
+	}
+	for _, h := range s.Holidays {
+		if _, err := time.Parse(dateLayout, h.Date); err != nil {
+			return &ValidationError{ErrScheduleInvalid, "holidays.date", "must be formatted as YYYY-MM-DD"}
+		}
+	}
+	return nil
+}
+
+func (s *Schedule) isHoliday(t time.Time) bool {
+	date := t.Format(dateLayout)
+	for _, h := range s.Holidays {
+		if h.Date == date {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOpen reports whether the device is within its opening hours at the given time.
+// A disabled schedule is always open.
+func (s *Schedule) IsOpen(now time.Time) bool {
+	if !s.Enabled {
+		return true
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return true
+	}
+	now = now.In(loc)
+	minute := now.Hour()*60 + now.Minute()
+
+	if !s.isHoliday(now) {
+		for _, i := range s.Weekly[weekdayName(now.Weekday())] {
+			start, _ := parseClock(i.Start)
+			end, _ := parseClock(i.End)
+			if minute >= start && (end <= start || minute < end) {
+				return true
+			}
+		}
+	}
+
+	// intervals of the previous day running past midnight
+	yesterday := now.AddDate(0, 0, -1)
+	if !s.isHoliday(yesterday) {
+		for _, i := range s.Weekly[weekdayName(yesterday.Weekday())] {
+			start, _ := parseClock(i.Start)
+			end, _ := parseClock(i.End)
+			if end <= start && minute < end {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type ScheduleRepo struct {
+	db *sql.DB
+}
+
+func (r *ScheduleRepo) Get(clientDeviceID string) (*Schedule, error) {
+	row := r.db.QueryRow(`SELECT client_device_id, enabled, timezone, weekly, holidays, away_message, away_cooldown_seconds, updated_at
+		FROM whatsmeow_extended_schedule
+		WHERE client_device_id = $1`,
+		clientDeviceID,
+	)
+	var i Schedule
+	var weekly, holidays []byte
+	err := row.Scan(
+		&i.ClientDeviceID,
+		&i.Enabled,
+		&i.Timezone,
+		&weekly,
+		&holidays,
+		&i.AwayMessage,
+		&i.AwayCooldownSeconds,
+		&i.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrScheduleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(weekly, &i.Weekly); err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(holidays, &i.Holidays)
+	return &i, err
+}
+
+func (r *ScheduleRepo) Save(s *Schedule) error {
+	weekly, err := json.Marshal(s.Weekly)
+	if err != nil {
+		return err
+	}
+	holidays, err := json.Marshal(s.Holidays)
+	if err != nil {
+		return err
+	}
+	row := r.db.QueryRow(`INSERT INTO
+		whatsmeow_extended_schedule (
+			client_device_id,
+			enabled,
+			timezone,
+			weekly,
+			holidays,
+			away_message,
+			away_cooldown_seconds
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (client_device_id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			timezone = EXCLUDED.timezone,
+			weekly = EXCLUDED.weekly,
+			holidays = EXCLUDED.holidays,
+			away_message = EXCLUDED.away_message,
+			away_cooldown_seconds = EXCLUDED.away_cooldown_seconds,
+			updated_at = now()
+		RETURNING updated_at`,
+		s.ClientDeviceID,
+		s.Enabled,
+		s.Timezone,
+		string(weekly),
+		string(holidays),
+		s.AwayMessage,
+		s.AwayCooldownSeconds,
+	)
+	return row.Scan(&s.UpdatedAt)
+}
+
+func (r *ScheduleRepo) Delete(clientDeviceID string) error {
+	res, err := r.db.Exec("DELETE FROM whatsmeow_extended_schedule WHERE client_device_id = $1", clientDeviceID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err == nil && n == 0 {
+		return ErrScheduleNotFound
+	}
+	return err
+}
@@ -0,0 +1,87 @@
+// Package avatar turns uploaded images into the square jpeg whatsapp expects for profile and group pictures.
+package avatar
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+
+	_ "image/gif"
+	_ "image/png"
+)
+
+const (
+	// Size is the largest side whatsapp keeps, bigger pictures are scaled down to it.
+	Size = 640
+	// MinSize is the smallest side accepted, whatsapp rejects smaller pictures.
+	MinSize = 192
+	// MaxPixels bounds the decoded image, a small file can claim a huge canvas.
+	MaxPixels = 40_000_000
+
+	quality = 90
+)
+
+var ErrInvalidImage = errors.New("picture must be a jpeg, png or gif image of at least 192x192 pixels and at most 40 megapixels")
+
+// Square center crops the image to a square, scales it down to at most Size and re-encodes it as jpeg.
+func Square(data []byte) ([]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if min(cfg.Width, cfg.Height) < MinSize || cfg.Width*cfg.Height > MaxPixels {
+		return nil, ErrInvalidImage
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+
+	b := src.Bounds()
+	side := min(b.Dx(), b.Dy())
+	crop := image.Rect(0, 0, side, side).Add(image.Pt(b.Min.X+(b.Dx()-side)/2, b.Min.Y+(b.Dy()-side)/2))
+
+	// flatten transparency on white, jpeg has no alpha
+	square := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(square, square.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(square, square.Bounds(), src, crop.Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err = jpeg.Encode(&buf, scale(square, min(side, Size)), &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// scale averages the source pixels covered by every destination pixel, good enough for downscaling photos.
+func scale(src *image.RGBA, size int) *image.RGBA {
+	side := src.Bounds().Dx()
+	if side == size {
+		return src
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		y0, y1 := y*side/size, max((y+1)*side/size, y*side/size+1)
+		for x := 0; x < size; x++ {
+			x0, x1 := x*side/size, max((x+1)*side/size, x*side/size+1)
+
+			var r, g, b, n uint32
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride+x0*4 : sy*src.Stride+x1*4]
+				for i := 0; i < len(row); i += 4 {
+					r += uint32(row[i])
+					g += uint32(row[i+1])
+					b += uint32(row[i+2])
+					n++
+				}
+			}
+			i := dst.PixOffset(x, y)
+			dst.Pix[i], dst.Pix[i+1], dst.Pix[i+2], dst.Pix[i+3] = uint8(r/n), uint8(g/n), uint8(b/n), 0xff
+		}
+	}
+	return dst
+}
@@ -0,0 +1,282 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	s3RequestTimeout = 2 * time.Minute
+	// MaxPresignExpiry is the longest validity SigV4 allows for a presigned url.
+	MaxPresignExpiry = 7 * 24 * time.Hour
+	retentionRuleID  = "whatsapp-api-media-retention"
+)
+
+var (
+	ErrInvalidS3Config      = errors.New("s3 endpoint and bucket are required")
+	ErrMissingS3Credentials = errors.New("s3 access key and secret key are required")
+)
+
+// S3Config points to a bucket of any S3-compatible storage (AWS, MinIO, R2, ...).
+// PathStyle addresses the bucket as endpoint/bucket instead of bucket.endpoint, which most self hosted servers need.
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	Prefix    string
+	AccessKey string
+	SecretKey string
+	PathStyle bool
+}
+
+// S3Store keeps blobs as objects under the configured prefix, requests are signed without the AWS sdk.
+type S3Store struct {
+	cfg    S3Config
+	base   *url.URL
+	signer *signer
+	http   *http.Client
+}
+
+type s3Error struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func NewS3Store(cfg S3Config) (*S3Store, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, ErrInvalidS3Config
+	}
+	if cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, ErrMissingS3Credentials
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	cfg.Prefix = strings.Trim(cfg.Prefix, "/")
+
+	base, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("s3 endpoint %q must be an absolute url", cfg.Endpoint)
+	}
+
+	return &S3Store{
+		cfg:    cfg,
+		base:   base,
+		signer: &signer{cfg.AccessKey, cfg.SecretKey, cfg.Region},
+		http:   &http.Client{Timeout: s3RequestTimeout},
+	}, nil
+}
+
+// url returns the address of the object with the given key, or of the bucket itself for an empty key.
+func (s *S3Store) url(key string) *url.URL {
+	u := *s.base
+	objectPath := ""
+	if key != "" {
+		objectPath = path.Join(s.cfg.Prefix, key)
+	}
+	if s.cfg.PathStyle {
+		u.Path = strings.TrimSuffix("/"+s.cfg.Bucket+"/"+objectPath, "/")
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+		u.Path = "/" + objectPath
+	}
+	u.RawPath = uriEncode(u.Path, true)
+	return &u
+}
+
+func (s *S3Store) do(ctx context.Context, method string, u *url.URL, body []byte, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.ContentLength = int64(len(body))
+
+	payloadHash := emptyPayloadHash
+	if len(body) > 0 {
+		payloadHash = hashHex(body)
+	}
+	s.signer.sign(req, payloadHash, time.Now())
+	return s.http.Do(req)
+}
+
+// checkResponse turns a non 2xx response into an error carrying the S3 error code, the body is closed on error.
+func checkResponse(res *http.Response) error {
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return nil
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	var e s3Error
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 64<<10))
+	if xml.Unmarshal(body, &e) != nil || e.Code == "" {
+		return fmt.Errorf("s3 request failed with status %d", res.StatusCode)
+	}
+	return fmt.Errorf("s3 request failed with status %d: %s %s", res.StatusCode, e.Code, e.Message)
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	res, err := s.do(ctx, http.MethodPut, s.url(key), data, header)
+	if err != nil {
+		return err
+	}
+	if err = checkResponse(res); err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	res, err := s.do(ctx, http.MethodGet, s.url(key), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkResponse(res); err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+func (s *S3Store) Exists(ctx context.Context, key string) (bool, error) {
+	res, err := s.do(ctx, http.MethodHead, s.url(key), nil, nil)
+	if err != nil {
+		return false, err
+	}
+	err = checkResponse(res)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, res.Body.Close()
+}
+
+// PresignGet returns a url that downloads the object directly from the storage until it expires.
+func (s *S3Store) PresignGet(_ context.Context, key string, expires time.Duration) (string, error) {
+	if expires <= 0 || expires > MaxPresignExpiry {
+		return "", fmt.Errorf("presigned url expiry must be between 1s and %v", MaxPresignExpiry)
+	}
+	return s.signer.presign(http.MethodGet, s.url(key), expires, time.Now()), nil
+}
+
+type lifecycleConfiguration struct {
+	XMLName xml.Name `xml:"LifecycleConfiguration"`
+	Rules   []any    `xml:"Rule"`
+}
+
+type lifecycleRule struct {
+	XMLName    xml.Name `xml:"Rule"`
+	ID         string   `xml:"ID"`
+	Prefix     string   `xml:"Filter>Prefix"`
+	Status     string   `xml:"Status"`
+	Expiration struct {
+		Days int `xml:"Days"`
+	} `xml:"Expiration"`
+}
+
+// rawLifecycleRule keeps a rule of someone else exactly as the bucket returned it.
+type rawLifecycleRule struct {
+	XMLName xml.Name `xml:"Rule"`
+	ID      string   `xml:"-"`
+	Inner   string   `xml:",innerxml"`
+}
+
+// lifecycleRules returns the current lifecycle rules of the bucket, none when it has no lifecycle.
+func (s *S3Store) lifecycleRules(ctx context.Context) ([]rawLifecycleRule, error) {
+	u := s.url("")
+	u.RawQuery = "lifecycle="
+	res, err := s.do(ctx, http.MethodGet, u, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	// a bucket without lifecycle answers NoSuchLifecycleConfiguration
+	if err = checkResponse(res); errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var current struct {
+		Rules []struct {
+			ID    string `xml:"ID"`
+			Inner string `xml:",innerxml"`
+		} `xml:"Rule"`
+	}
+	if err = xml.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(&current); err != nil {
+		return nil, err
+	}
+	rules := make([]rawLifecycleRule, 0, len(current.Rules))
+	for _, rule := range current.Rules {
+		rules = append(rules, rawLifecycleRule{ID: rule.ID, Inner: rule.Inner})
+	}
+	return rules, nil
+}
+
+// SetRetention installs a bucket lifecycle rule expiring objects under the prefix after the given days.
+// The other rules of the bucket are kept, only the rule of this service is added or replaced.
+func (s *S3Store) SetRetention(ctx context.Context, days int) error {
+	if days <= 0 {
+		return fmt.Errorf("retention must be at least one day, got %d", days)
+	}
+	existing, err := s.lifecycleRules(ctx)
+	if err != nil {
+		return err
+	}
+
+	rule := lifecycleRule{ID: retentionRuleID, Status: "Enabled"}
+	if s.cfg.Prefix != "" {
+		rule.Prefix = s.cfg.Prefix + "/"
+	}
+	rule.Expiration.Days = days
+
+	rules := []any{rule}
+	for _, other := range existing {
+		if other.ID != retentionRuleID {
+			rules = append(rules, other)
+		}
+	}
+	body, err := xml.Marshal(lifecycleConfiguration{Rules: rules})
+	if err != nil {
+		return err
+	}
+	sum := md5.Sum(body)
+	header := http.Header{}
+	header.Set("Content-Type", "application/xml")
+	header.Set("Content-Md5", base64.StdEncoding.EncodeToString(sum[:]))
+
+	u := s.url("")
+	u.RawQuery = "lifecycle="
+	res, err := s.do(ctx, http.MethodPut, u, body, header)
+	if err != nil {
+		return err
+	}
+	if err = checkResponse(res); err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
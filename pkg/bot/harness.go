@@ -0,0 +1,95 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+// HarnessDevice is the client device id used by the harness when none is set.
+const HarnessDevice = "harness"
+
+type Reply struct {
+	To      types.JID
+	Text    string
+	Message *waE2E.Message
+}
+
+// Harness feeds synthetic messages to a router and records its replies instead of sending them,
+// so bots can be tested without a paired device:
+//
+//	h := bot.NewHarness(router)
+//	replies := h.Send("6281234567890", "/status 1234")
+type Harness struct {
+	mut            sync.Mutex
+	Router         *Router
+	ClientDeviceID string
+	replies        []Reply
+}
+
+func NewHarness(router *Router) *Harness {
+	return &Harness{
+		Router:         router,
+		ClientDeviceID: HarnessDevice,
+	}
+}
+
+// SendMessage implements Sender.
+func (h *Harness) SendMessage(_ context.Context, _ *whatsmeow.Client, _ string, to types.JID, msg *waE2E.Message, _ ...whatsmeow.SendRequestExtra) (whatsmeow.SendResponse, error) {
+	h.mut.Lock()
+	defer h.mut.Unlock()
+	h.replies = append(h.replies, Reply{To: to, Text: whatsapp.MessageText(msg), Message: msg})
+	return whatsmeow.SendResponse{Timestamp: time.Now()}, nil
+}
+
+// Dispatch routes the event and returns the replies it produced.
+func (h *Harness) Dispatch(evt *events.Message) []Reply {
+	h.mut.Lock()
+	h.replies = nil
+	h.mut.Unlock()
+
+	h.Router.Dispatch(context.Background(), h, nil, h.ClientDeviceID, evt)
+
+	h.mut.Lock()
+	defer h.mut.Unlock()
+	return h.replies
+}
+
+// Send simulates a private message from the given phone number.
+func (h *Harness) Send(from string, text string) []Reply {
+	sender := types.NewJID(from, types.DefaultUserServer)
+	return h.Dispatch(NewMessage(sender, sender, text))
+}
+
+// SendGroup simulates a message from the given phone number inside a group.
+func (h *Harness) SendGroup(group string, from string, text string) []Reply {
+	return h.Dispatch(NewMessage(types.NewJID(group, types.GroupServer), types.NewJID(from, types.DefaultUserServer), text))
+}
+
+var messageCounter atomic.Int64
+
+// NewMessage builds a synthetic incoming text message.
+func NewMessage(chat types.JID, sender types.JID, text string) *events.Message {
+	return &events.Message{
+		Info: types.MessageInfo{
+			MessageSource: types.MessageSource{
+				Chat:    chat,
+				Sender:  sender,
+				IsGroup: chat.Server == types.GroupServer,
+			},
+			ID:        types.MessageID(fmt.Sprintf("HARNESS%d", messageCounter.Add(1))),
+			Type:      "text",
+			Timestamp: time.Now(),
+		},
+		Message: &waE2E.Message{Conversation: proto.String(text)},
+	}
+}
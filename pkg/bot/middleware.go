@@ -0,0 +1,89 @@
+package bot
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// AllowSenders only lets the given phone numbers (or full JIDs) run the command.
+func AllowSenders(senders ...string) Middleware {
+	allowed := make(map[string]bool)
+	for _, s := range senders {
+		allowed[strings.TrimPrefix(s, "+")] = true
+	}
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			sender := c.Sender()
+			if !allowed[sender.User] && !allowed[sender.String()] {
+				return ErrUnauthorized
+			}
+			return next(c)
+		}
+	}
+}
+
+// senderHits are the recent commands of a sender and when they were last told about the limit.
+type senderHits struct {
+	recent   []time.Time
+	notified time.Time
+}
+
+// RateLimit allows every sender at most limit commands within the given window. A limited sender is told
+// once per window, further commands are dropped silently so a flood does not cause one reply per message.
+func RateLimit(limit int, window time.Duration) Middleware {
+	var mut sync.Mutex
+	hits := make(map[string]*senderHits)
+	var lastSweep time.Time
+
+	// prune drops the hits outside the window and reports whether the sender can be forgotten.
+	prune := func(h *senderHits, now time.Time) bool {
+		recent := h.recent[:0]
+		for _, t := range h.recent {
+			if now.Sub(t) < window {
+				recent = append(recent, t)
+			}
+		}
+		h.recent = recent
+		return len(recent) == 0 && now.Sub(h.notified) >= window
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			key := c.ClientDeviceID + "|" + c.Sender().String()
+			now := time.Now()
+
+			mut.Lock()
+			// senders who went quiet are forgotten
+			if now.Sub(lastSweep) >= window {
+				for k, h := range hits {
+					if prune(h, now) {
+						delete(hits, k)
+					}
+				}
+				lastSweep = now
+			}
+			h, ok := hits[key]
+			if !ok {
+				h = &senderHits{}
+				hits[key] = h
+			}
+			prune(h, now)
+			if len(h.recent) >= limit {
+				notify := now.Sub(h.notified) >= window
+				if notify {
+					h.notified = now
+				}
+				mut.Unlock()
+				if notify {
+					return ErrRateLimited
+				}
+				return nil
+			}
+			h.recent = append(h.recent, now)
+			mut.Unlock()
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	waLog "go.mau.fi/whatsmeow/util/log"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	DefaultPrefix   = "/"
+	DefaultStateTTL = 30 * time.Minute
+)
+
+var (
+	ErrUnknownCommand = errors.New("unknown command, send /help to list the available commands")
+	ErrUnauthorized   = errors.New("you are not allowed to use this command")
+	ErrRateLimited    = errors.New("too many commands, please try again later")
+)
+
+// Sender delivers replies, it is satisfied by *whatsapp.Client so every reply goes through its send path.
+type Sender interface {
+	SendMessage(ctx context.Context, cli *whatsmeow.Client, clientDeviceID string, to types.JID, msg *waE2E.Message, extra ...whatsmeow.SendRequestExtra) (whatsmeow.SendResponse, error)
+}
+
+type HandlerFunc func(c *Context) error
+type Middleware func(next HandlerFunc) HandlerFunc
+type ErrorHandler func(c *Context, err error)
+type RouterOption func(r *Router)
+
+type Command struct {
+	Name        string
+	Usage       string
+	Description string
+	Handler     HandlerFunc
+	Middlewares []Middleware
+}
+
+// Context is handed to command handlers, it describes the incoming command and allows replying to it.
+type Context struct {
+	context.Context
+
+	ClientDeviceID string
+	Event          *events.Message
+	Command        string
+	Args           []string
+	RawArgs        string
+	State          *State
+
+	cli    *whatsmeow.Client
+	sender Sender
+}
+
+func (c *Context) Chat() types.JID {
+	return c.Event.Info.Chat
+}
+
+func (c *Context) Sender() types.JID {
+	return c.Event.Info.Sender.ToNonAD()
+}
+
+// Arg returns the i-th argument, or an empty string when there are not that many.
+func (c *Context) Arg(i int) string {
+	if i < 0 || i >= len(c.Args) {
+		return ""
+	}
+	return c.Args[i]
+}
+
+func (c *Context) ReplyMessage(msg *waE2E.Message) error {
+	_, err := c.sender.SendMessage(c, c.cli, c.ClientDeviceID, c.Chat(), msg)
+	return err
+}
+
+func (c *Context) Reply(text string) error {
+	return c.ReplyMessage(&waE2E.Message{Conversation: proto.String(text)})
+}
+
+func (c *Context) Replyf(format string, a ...any) error {
+	return c.Reply(fmt.Sprintf(format, a...))
+}
+
+// Router dispatches incoming messages starting with the prefix to the registered commands.
+type Router struct {
+	prefix      string
+	commands    map[string]*Command
+	middlewares []Middleware
+	states      *StateStore
+	onError     ErrorHandler
+	log         waLog.Logger
+}
+
+func WithPrefix(prefix string) RouterOption {
+	return func(r *Router) {
+		r.prefix = prefix
+	}
+}
+
+func WithStateTTL(ttl time.Duration) RouterOption {
+	return func(r *Router) {
+		r.states = NewStateStore(ttl)
+	}
+}
+
+func WithErrorHandler(handler ErrorHandler) RouterOption {
+	return func(r *Router) {
+		r.onError = handler
+	}
+}
+
+func NewRouter(opts ...RouterOption) *Router {
+	r := &Router{
+		prefix:   DefaultPrefix,
+		commands: make(map[string]*Command),
+		states:   NewStateStore(DefaultStateTTL),
+		log:      waLog.Stdout("Bot", whatsapp.LogLevel, true),
+	}
+	r.onError = r.defaultErrorHandler
+
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Use adds middlewares applied to every command, in the order they are given.
+func (r *Router) Use(mws ...Middleware) {
+	r.middlewares = append(r.middlewares, mws...)
+}
+
+func (r *Router) Register(cmd Command) {
+	r.commands[strings.ToLower(cmd.Name)] = &cmd
+}
+
+func (r *Router) Handle(name string, handler HandlerFunc, mws ...Middleware) {
+	r.Register(Command{Name: name, Handler: handler, Middlewares: mws})
+}
+
+func (r *Router) Commands() []*Command {
+	cmds := make([]*Command, 0, len(r.commands))
+	for _, cmd := range r.commands {
+		cmds = append(cmds, cmd)
+	}
+	sort.Slice(cmds, func(i, j int) bool { return cmds[i].Name < cmds[j].Name })
+	return cmds
+}
+
+// HelpHandler lists the registered commands with their usage and description.
+func (r *Router) HelpHandler() HandlerFunc {
+	return func(c *Context) error {
+		lines := make([]string, 0, len(r.commands))
+		for _, cmd := range r.Commands() {
+			line := r.prefix + cmd.Name
+			if cmd.Usage != "" {
+				line += " " + cmd.Usage
+			}
+			if cmd.Description != "" {
+				line += " - " + cmd.Description
+			}
+			lines = append(lines, line)
+		}
+		return c.Reply(strings.Join(lines, "\n"))
+	}
+}
+
+func (r *Router) defaultErrorHandler(c *Context, err error) {
+	switch {
+	case errors.Is(err, ErrUnknownCommand),
+		errors.Is(err, ErrUnauthorized),
+		errors.Is(err, ErrRateLimited),
+		errors.Is(err, ErrUnterminatedQuote):
+		_ = c.Reply(err.Error())
+	default:
+		r.log.Errorf("command %v failed for client id: %v, %v", c.Command, c.ClientDeviceID, err)
+	}
+}
+
+// Route implements whatsapp.CommandRouter.
+func (r *Router) Route(c *whatsapp.Client, cli *whatsmeow.Client, clientDeviceID string, evt *events.Message) bool {
+	return r.Dispatch(context.Background(), c, cli, clientDeviceID, evt)
+}
+
+// Dispatch runs the command of the message and reports whether the message was meant for the router.
+func (r *Router) Dispatch(ctx context.Context, sender Sender, cli *whatsmeow.Client, clientDeviceID string, evt *events.Message) bool {
+	if evt.Info.IsFromMe || strings.Contains(evt.Info.SourceString(), "broadcast") {
+		return false
+	}
+
+	text := strings.TrimSpace(whatsapp.MessageText(evt.Message))
+	state := r.states.Get(clientDeviceID, evt.Info.Chat.String())
+	c := &Context{
+		Context:        ctx,
+		ClientDeviceID: clientDeviceID,
+		Event:          evt,
+		State:          state,
+		cli:            cli,
+		sender:         sender,
+	}
+
+	// only registered commands are taken, so paths, "/ hello" or the commands of other bots pass through
+	var cmd *Command
+	if rest, ok := strings.CutPrefix(text, r.prefix); ok {
+		name, rawArgs, _ := strings.Cut(rest, " ")
+		if cmd = r.commands[strings.ToLower(name)]; cmd != nil && name != "" {
+			c.Command = strings.ToLower(name)
+			c.RawArgs = strings.TrimSpace(rawArgs)
+		}
+	}
+	if c.Command == "" {
+		expect := state.Expecting()
+		if expect == "" || text == "" {
+			return false
+		}
+		state.Expect("")
+		c.Command = expect
+		c.RawArgs = text
+		if cmd = r.commands[expect]; cmd == nil {
+			r.onError(c, ErrUnknownCommand)
+			return true
+		}
+	}
+	args, err := ParseArgs(c.RawArgs)
+	if err != nil {
+		r.onError(c, err)
+		return true
+	}
+	c.Args = args
+
+	handler := cmd.Handler
+	for i := len(cmd.Middlewares) - 1; i >= 0; i-- {
+		handler = cmd.Middlewares[i](handler)
+	}
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		handler = r.middlewares[i](handler)
+	}
+	if err := handler(c); err != nil {
+		r.onError(c, err)
+	}
+	return true
+}
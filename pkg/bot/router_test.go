@@ -0,0 +1,181 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hrz8/whatsapp-api/pkg/whatsapp"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+		err  error
+	}{
+		{in: "", want: []string{}},
+		{in: "1234", want: []string{"1234"}},
+		{in: "  a   b\tc ", want: []string{"a", "b", "c"}},
+		{in: `"hello world" x`, want: []string{"hello world", "x"}},
+		{in: `'say "hi"'`, want: []string{`say "hi"`}},
+		{in: `a\ b`, want: []string{"a b"}},
+		{in: `""`, want: []string{""}},
+		{in: `"open`, err: ErrUnterminatedQuote},
+		{in: `trailing\`, err: ErrUnterminatedQuote},
+	}
+	for _, tt := range tests {
+		got, err := ParseArgs(tt.in)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("ParseArgs(%q) error = %v, want %v", tt.in, err, tt.err)
+			continue
+		}
+		if tt.err == nil && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseArgs(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func newTestRouter() *Router {
+	r := NewRouter()
+	r.Register(Command{
+		Name:  "status",
+		Usage: "<order>",
+		Handler: func(c *Context) error {
+			return c.Replyf("order %s is on its way", c.Arg(0))
+		},
+	})
+	return r
+}
+
+func replyTexts(replies []Reply) []string {
+	texts := make([]string, 0, len(replies))
+	for _, reply := range replies {
+		texts = append(texts, reply.Text)
+	}
+	return texts
+}
+
+func TestHarnessCommand(t *testing.T) {
+	h := NewHarness(newTestRouter())
+
+	replies := h.Send("6281234567890", "/STATUS 1234")
+	if got, want := replyTexts(replies), []string{"order 1234 is on its way"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("replies = %q, want %q", got, want)
+	}
+	if to := replies[0].To.String(); to != "6281234567890@s.whatsapp.net" {
+		t.Errorf("reply went to %v", to)
+	}
+
+	replies = h.SendGroup("120363025246125486", "6281234567890", "/status 42")
+	if len(replies) != 1 || replies[0].To.String() != "120363025246125486@g.us" {
+		t.Errorf("group reply = %+v", replies)
+	}
+}
+
+func TestHarnessIgnoresOtherMessages(t *testing.T) {
+	h := NewHarness(newTestRouter())
+	if replies := h.Send("6281234567890", "hello"); len(replies) != 0 {
+		t.Errorf("plain message got replies %q", replyTexts(replies))
+	}
+	for _, text := range []string{"/", "/ hello", "/home/user/notes.txt", "/unknown", "/other 1"} {
+		if replies := h.Send("6281234567890", text); len(replies) != 0 {
+			t.Errorf("%q got replies %q", text, replyTexts(replies))
+		}
+	}
+}
+
+func TestDispatchOnlyTakesRegisteredCommands(t *testing.T) {
+	r := newTestRouter()
+	sender := types.NewJID("6281234567890", types.DefaultUserServer)
+	group := types.NewJID("120363025246125486", types.GroupServer)
+	h := NewHarness(r)
+
+	tests := []struct {
+		evt  *events.Message
+		want bool
+	}{
+		{NewMessage(sender, sender, "/status 1"), true},
+		{NewMessage(group, sender, "/status 1"), true},
+		{NewMessage(sender, sender, "/unknown"), false},
+		{NewMessage(group, sender, "/otherbot ping"), false},
+		{NewMessage(sender, sender, "/ hello"), false},
+	}
+	for _, tt := range tests {
+		if got := r.Dispatch(context.Background(), h, nil, h.ClientDeviceID, tt.evt); got != tt.want {
+			t.Errorf("Dispatch(%q) = %v, want %v", whatsapp.MessageText(tt.evt.Message), got, tt.want)
+		}
+	}
+}
+
+func TestHarnessErrors(t *testing.T) {
+	h := NewHarness(newTestRouter())
+	if got, want := replyTexts(h.Send("6281234567890", `/status "1234`)), []string{ErrUnterminatedQuote.Error()}; !reflect.DeepEqual(got, want) {
+		t.Errorf("bad quoting replies = %q, want %q", got, want)
+	}
+}
+
+func TestAllowSenders(t *testing.T) {
+	r := newTestRouter()
+	r.Handle("admin", func(c *Context) error { return c.Reply("welcome") }, AllowSenders("+6281111111111"))
+	h := NewHarness(r)
+
+	if got := replyTexts(h.Send("6281111111111", "/admin")); !reflect.DeepEqual(got, []string{"welcome"}) {
+		t.Errorf("allowed sender replies = %q", got)
+	}
+	if got := replyTexts(h.Send("6282222222222", "/admin")); !reflect.DeepEqual(got, []string{ErrUnauthorized.Error()}) {
+		t.Errorf("other sender replies = %q", got)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	r := newTestRouter()
+	r.Use(RateLimit(2, time.Minute))
+	h := NewHarness(r)
+
+	for i := 0; i < 2; i++ {
+		if got := replyTexts(h.Send("6281234567890", "/status 1")); len(got) != 1 || got[0] == ErrRateLimited.Error() {
+			t.Fatalf("command %d replies = %q", i, got)
+		}
+	}
+	if got := replyTexts(h.Send("6281234567890", "/status 1")); !reflect.DeepEqual(got, []string{ErrRateLimited.Error()}) {
+		t.Errorf("third command replies = %q", got)
+	}
+	// the sender is only told once per window
+	if got := h.Send("6281234567890", "/status 1"); len(got) != 0 {
+		t.Errorf("fourth command replies = %q", replyTexts(got))
+	}
+	if got := replyTexts(h.Send("6289999999999", "/status 1")); len(got) != 1 || got[0] == ErrRateLimited.Error() {
+		t.Errorf("other sender is limited too: %q", got)
+	}
+}
+
+func TestStateConversation(t *testing.T) {
+	r := NewRouter()
+	r.Handle("order", func(c *Context) error {
+		c.State.Expect("address")
+		c.State.Set("item", c.Arg(0))
+		return c.Reply("where should we deliver it?")
+	})
+	r.Handle("address", func(c *Context) error {
+		defer c.State.Clear()
+		return c.Replyf("sending %s to %s", c.State.Get("item"), c.RawArgs)
+	})
+	h := NewHarness(r)
+
+	h.Send("6281234567890", "/order coffee")
+	// another chat has its own state
+	if got := h.Send("6289999999999", "Jl. Sudirman 1"); len(got) != 0 {
+		t.Errorf("other chat replies = %q", replyTexts(got))
+	}
+	if got, want := replyTexts(h.Send("6281234567890", "Jl. Sudirman 1")), []string{"sending coffee to Jl. Sudirman 1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("follow up replies = %q, want %q", got, want)
+	}
+	if got := h.Send("6281234567890", "thanks"); len(got) != 0 {
+		t.Errorf("cleared state still expects: %q", replyTexts(got))
+	}
+}
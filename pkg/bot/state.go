@@ -0,0 +1,84 @@
+package bot
+
+import (
+	"sync"
+	"time"
+)
+
+// State is the conversation state of a single chat, it lives until it is cleared or expires.
+type State struct {
+	mut       sync.RWMutex
+	expect    string
+	data      map[string]string
+	updatedAt time.Time
+}
+
+func (s *State) Get(key string) string {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+	return s.data[key]
+}
+
+func (s *State) Set(key string, value string) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	s.data[key] = value
+}
+
+// Expect routes the next message of the chat which is not a command to the given command,
+// with the whole text as its arguments. This is how multi step conversations are built.
+func (s *State) Expect(command string) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	s.expect = command
+}
+
+func (s *State) Expecting() string {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+	return s.expect
+}
+
+func (s *State) Clear() {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	s.expect = ""
+	s.data = make(map[string]string)
+}
+
+type StateStore struct {
+	mut    sync.Mutex
+	ttl    time.Duration
+	states map[string]*State
+}
+
+func NewStateStore(ttl time.Duration) *StateStore {
+	return &StateStore{
+		ttl:    ttl,
+		states: make(map[string]*State),
+	}
+}
+
+// Get returns the state of the chat, a fresh one is created when it does not exist or has expired.
+func (s *StateStore) Get(clientDeviceID string, chat string) *State {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	now := time.Now()
+	key := clientDeviceID + "|" + chat
+	state, ok := s.states[key]
+	if !ok || (s.ttl > 0 && now.Sub(state.updatedAt) > s.ttl) {
+		state = &State{data: make(map[string]string)}
+		s.states[key] = state
+	}
+	state.updatedAt = now
+
+	if len(s.states) > 1024 && s.ttl > 0 {
+		for k, v := range s.states {
+			if now.Sub(v.updatedAt) > s.ttl {
+				delete(s.states, k)
+			}
+		}
+	}
+	return state
+}
@@ -0,0 +1,244 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	DefaultMaxSize   = 16 << 20
+	DefaultTimeout   = 30 * time.Second
+	DefaultCacheSize = 64 << 20
+	maxRedirects     = 5
+)
+
+var (
+	ErrInvalidURL     = errors.New("media url must be an absolute http or https url")
+	ErrHostNotAllowed = errors.New("media url host is not allowed")
+	ErrTooLarge       = errors.New("media at url exceeds the size limit")
+	ErrFetchFailed    = errors.New("cannot fetch media from url")
+)
+
+// Config limits what a Fetcher downloads. AllowedHosts entries match a host exactly,
+// or any of its subdomains when prefixed with "*." (e.g. "*.example.com"); an empty list allows no host.
+// Whatever the list, loopback, private and link-local addresses are never dialed.
+// CacheSize is the total bytes kept in memory for revalidation by ETag, a negative value disables the cache.
+type Config struct {
+	MaxSize      int64
+	Timeout      time.Duration
+	AllowedHosts []string
+	CacheSize    int64
+}
+
+type File struct {
+	Data        []byte
+	ContentType string
+	Filename    string
+}
+
+type cacheEntry struct {
+	etag string
+	file *File
+	used time.Time
+}
+
+// Fetcher downloads remote files, caching them by url and revalidating with If-None-Match.
+type Fetcher struct {
+	cfg  Config
+	http *http.Client
+
+	mut   sync.Mutex
+	cache map[string]*cacheEntry
+	size  int64
+}
+
+func New(cfg Config) *Fetcher {
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = DefaultMaxSize
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.CacheSize == 0 {
+		cfg.CacheSize = DefaultCacheSize
+	}
+
+	dialer := &net.Dialer{Timeout: cfg.Timeout, Control: dialControl}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	f := &Fetcher{cfg: cfg, cache: make(map[string]*cacheEntry)}
+	f.http = &http.Client{
+		Transport: transport,
+		Timeout:   cfg.Timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			if !f.allowed(req.URL) {
+				return ErrHostNotAllowed
+			}
+			return nil
+		},
+	}
+	return f
+}
+
+// dialControl runs after dns resolution and for every redirect, so a public host name
+// pointing to an internal address is refused too.
+func dialControl(_ string, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return ErrHostNotAllowed
+	}
+	ip := addrPort.Addr().Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified() || ip.IsMulticast() {
+		return ErrHostNotAllowed
+	}
+	return nil
+}
+
+func (f *Fetcher) allowed(u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+	for _, allowed := range f.cfg.AllowedHosts {
+		allowed = strings.ToLower(allowed)
+		if suffix, ok := strings.CutPrefix(allowed, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+		} else if host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// Fetch downloads the file at rawURL, an unchanged file is served from the cache.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (*File, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, ErrInvalidURL
+	}
+	if !f.allowed(u) {
+		return nil, ErrHostNotAllowed
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, ErrInvalidURL
+	}
+	cached := f.cached(rawURL)
+	if cached != nil {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	res, err := f.http.Do(req)
+	if errors.Is(err, ErrHostNotAllowed) {
+		return nil, ErrHostNotAllowed
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFetchFailed, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotModified && cached != nil {
+		return cached.file, nil
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: unexpected status %d", ErrFetchFailed, res.StatusCode)
+	}
+	if res.ContentLength > f.cfg.MaxSize {
+		return nil, ErrTooLarge
+	}
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, f.cfg.MaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFetchFailed, err)
+	}
+	if int64(len(data)) > f.cfg.MaxSize {
+		return nil, ErrTooLarge
+	}
+
+	file := &File{
+		Data:        data,
+		ContentType: contentType(res.Header.Get("Content-Type"), data),
+		Filename:    filename(res),
+	}
+	if etag := res.Header.Get("ETag"); etag != "" {
+		f.store(rawURL, etag, file)
+	}
+	return file, nil
+}
+
+// contentType trusts the server unless it only claims a generic type, then the data is sniffed.
+func contentType(header string, data []byte) string {
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err != nil || mediaType == "application/octet-stream" || mediaType == "binary/octet-stream" {
+		return http.DetectContentType(data)
+	}
+	return mediaType
+}
+
+func filename(res *http.Response) string {
+	if _, params, err := mime.ParseMediaType(res.Header.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+		return path.Base(params["filename"])
+	}
+	// the final url, after redirects
+	name := path.Base(res.Request.URL.Path)
+	if name == "." || name == "/" {
+		return ""
+	}
+	return name
+}
+
+func (f *Fetcher) cached(rawURL string) *cacheEntry {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	entry := f.cache[rawURL]
+	if entry != nil {
+		entry.used = time.Now()
+	}
+	return entry
+}
+
+// store caches the file, evicting the least recently used entries to stay within the cache size.
+func (f *Fetcher) store(rawURL string, etag string, file *File) {
+	size := int64(len(file.Data))
+	if f.cfg.CacheSize < 0 || size > f.cfg.CacheSize {
+		return
+	}
+
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	if old := f.cache[rawURL]; old != nil {
+		f.size -= int64(len(old.file.Data))
+		delete(f.cache, rawURL)
+	}
+	for f.size+size > f.cfg.CacheSize {
+		var oldestURL string
+		var oldest *cacheEntry
+		for u, e := range f.cache {
+			if oldest == nil || e.used.Before(oldest.used) {
+				oldestURL, oldest = u, e
+			}
+		}
+		f.size -= int64(len(oldest.file.Data))
+		delete(f.cache, oldestURL)
+	}
+	f.cache[rawURL] = &cacheEntry{etag, file, time.Now()}
+	f.size += size
+}
@@ -0,0 +1,113 @@
+// Package phone normalizes phone numbers as users type them into E.164 digits.
+package phone
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// E.164 numbers have at most 15 digits including the country code, the shortest ones in use have 7.
+const (
+	MinLength = 7
+	MaxLength = 15
+)
+
+type Reason string
+
+const (
+	ReasonEmpty              Reason = "empty"
+	ReasonInvalidCharacter   Reason = "invalid_character"
+	ReasonMissingCountryCode Reason = "missing_country_code"
+	ReasonInvalidCountryCode Reason = "invalid_country_code"
+	ReasonTooShort           Reason = "too_short"
+	ReasonTooLong            Reason = "too_long"
+)
+
+var ErrInvalid = errors.New("phone number is invalid")
+
+// Error tells why a phone number was rejected, it matches ErrInvalid.
+type Error struct {
+	Input  string `json:"input"`
+	Reason Reason `json:"reason"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("phone number %q is invalid: %s", e.Input, strings.ReplaceAll(string(e.Reason), "_", " "))
+}
+
+func (e *Error) Unwrap() error {
+	return ErrInvalid
+}
+
+// separators are dropped wherever they appear, e.g. "+62 (812) 3456-7890"
+const separators = " -.()/\t\u00a0"
+
+// ValidCountryCode reports whether cc is a calling code: 1 to 3 digits not starting with 0.
+func ValidCountryCode(cc string) bool {
+	cc = strings.TrimPrefix(cc, "+")
+	return len(cc) > 0 && len(cc) <= 3 && cc[0] != '0' && isDigits(cc)
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Normalize returns the number as E.164 digits without the leading "+".
+// International numbers start with "+" or "00". National numbers start with the trunk prefix "0",
+// which is replaced by defaultCountry, so they fail without one. Numbers without either prefix
+// are taken as already carrying their country code.
+func Normalize(input string, defaultCountry string) (string, error) {
+	fail := func(reason Reason) (string, error) {
+		return "", &Error{Input: input, Reason: reason}
+	}
+
+	s := strings.TrimSpace(input)
+	international := strings.HasPrefix(s, "+")
+	s = strings.TrimPrefix(s, "+")
+
+	var b strings.Builder
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			b.WriteRune(c)
+		case strings.ContainsRune(separators, c):
+		default:
+			return fail(ReasonInvalidCharacter)
+		}
+	}
+	digits := b.String()
+	if digits == "" {
+		return fail(ReasonEmpty)
+	}
+
+	switch {
+	case international:
+	case strings.HasPrefix(digits, "00"):
+		digits = digits[2:]
+	case strings.HasPrefix(digits, "0"):
+		if defaultCountry == "" {
+			return fail(ReasonMissingCountryCode)
+		}
+		if !ValidCountryCode(defaultCountry) {
+			return fail(ReasonInvalidCountryCode)
+		}
+		digits = strings.TrimPrefix(defaultCountry, "+") + digits[1:]
+	}
+
+	if strings.HasPrefix(digits, "0") {
+		return fail(ReasonInvalidCountryCode)
+	}
+	if len(digits) < MinLength {
+		return fail(ReasonTooShort)
+	}
+	if len(digits) > MaxLength {
+		return fail(ReasonTooLong)
+	}
+	return digits, nil
+}
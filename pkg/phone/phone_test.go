@@ -0,0 +1,45 @@
+package phone
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in             string
+		defaultCountry string
+		want           string
+		reason         Reason
+	}{
+		{in: "0812-3456-7890", defaultCountry: "62", want: "6281234567890"},
+		{in: "0812-3456-7890", defaultCountry: "+62", want: "6281234567890"},
+		{in: "+62 812 3456 7890", want: "6281234567890"},
+		{in: "(0812) 3456.7890", defaultCountry: "62", want: "6281234567890"},
+		{in: "+62 (812) 3456-7890", want: "6281234567890"},
+		{in: "0062 812 3456 7890", defaultCountry: "1", want: "6281234567890"},
+		{in: "6281234567890", want: "6281234567890"},
+		{in: "  ", reason: ReasonEmpty},
+		{in: "0812-abc", defaultCountry: "62", reason: ReasonInvalidCharacter},
+		{in: "0812-3456-7890", reason: ReasonMissingCountryCode},
+		{in: "0812-3456-7890", defaultCountry: "0", reason: ReasonInvalidCountryCode},
+		{in: "+0812 3456 7890", reason: ReasonInvalidCountryCode},
+		{in: "+62 812", reason: ReasonTooShort},
+		{in: "0812", defaultCountry: "62", reason: ReasonTooShort},
+		{in: "+62 812 3456 7890 1234", reason: ReasonTooLong},
+		{in: "00 62 812 3456 7890 1234", reason: ReasonTooLong},
+	}
+	for _, tt := range tests {
+		got, err := Normalize(tt.in, tt.defaultCountry)
+		if tt.reason != "" {
+			var perr *Error
+			if !errors.As(err, &perr) || perr.Reason != tt.reason || !errors.Is(err, ErrInvalid) {
+				t.Errorf("Normalize(%q, %q) error = %v, want reason %v", tt.in, tt.defaultCountry, err, tt.reason)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Normalize(%q, %q) = %q, %v, want %q", tt.in, tt.defaultCountry, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package whatsapp
+
+import (
+	"sync"
+	"time"
+)
+
+const eventBufferSize = 256
+
+// Event is what the API delivers to its consumers, Data is the event specific payload.
+type Event struct {
+	ID             uint64    `json:"id"`
+	ClientDeviceID string    `json:"client_device_id"`
+	Type           string    `json:"type"`
+	Timestamp      time.Time `json:"timestamp"`
+	Data           any       `json:"data"`
+}
+
+type subscription struct {
+	clientDeviceID string
+	ch             chan *Event
+}
+
+// Broker fans events out to the current subscribers, it keeps no history.
+// A subscriber that does not keep up loses events instead of blocking the whatsapp clients.
+type Broker struct {
+	mut  sync.Mutex
+	seq  uint64
+	subs map[*subscription]struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{subs: make(map[*subscription]struct{})}
+}
+
+// Subscribe returns the events of the given device, or of every device for an empty id.
+// The returned function unsubscribes and must be called once the consumer is gone.
+func (b *Broker) Subscribe(clientDeviceID string) (<-chan *Event, func()) {
+	sub := &subscription{clientDeviceID, make(chan *Event, eventBufferSize)}
+
+	b.mut.Lock()
+	b.subs[sub] = struct{}{}
+	b.mut.Unlock()
+
+	return sub.ch, func() {
+		b.mut.Lock()
+		defer b.mut.Unlock()
+		if _, ok := b.subs[sub]; ok {
+			delete(b.subs, sub)
+			close(sub.ch)
+		}
+	}
+}
+
+// Publish delivers the event and reports how many subscribers missed it because they were full.
+func (b *Broker) Publish(clientDeviceID string, eventType string, data any) (dropped int) {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+
+	b.seq++
+	evt := &Event{
+		ID:             b.seq,
+		ClientDeviceID: clientDeviceID,
+		Type:           eventType,
+		Timestamp:      time.Now().UTC(),
+		Data:           data,
+	}
+	for sub := range b.subs {
+		if sub.clientDeviceID != "" && sub.clientDeviceID != clientDeviceID {
+			continue
+		}
+		select {
+		case sub.ch <- evt:
+		default:
+			dropped++
+		}
+	}
+	return dropped
+}
+
+func (c *Client) publish(clientDeviceID string, eventType string, data any) {
+	if dropped := c.events.Publish(clientDeviceID, eventType, data); dropped > 0 {
+		c.log.Warnf("%d event consumers missed %v event for client id: %v", dropped, eventType, clientDeviceID)
+	}
+}
@@ -0,0 +1,272 @@
+package whatsapp
+
+import (
+	"errors"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/appstate"
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+var ErrInvalidMuteDuration = errors.New("mute duration must not be negative")
+
+// chatState changes the stored chat, nil fields are kept. MutedUntil is only applied along with Muted.
+type chatState struct {
+	Archived   *bool
+	Pinned     *bool
+	Muted      *bool
+	MutedUntil *time.Time
+	Unread     *bool
+	ClearedAt  *time.Time
+	DeletedAt  *time.Time
+}
+
+// saveChatState creates the chat or updates its state, chats changed on the phone may not be stored yet.
+func (r *MessageRepo) saveChatState(clientDeviceID string, jid string, s *chatState) error {
+	_, err := r.db.Exec(`INSERT INTO
+		whatsmeow_extended_chat (
+			client_device_id,
+			jid,
+			archived,
+			pinned,
+			muted,
+			muted_until,
+			unread,
+			cleared_at,
+			deleted_at
+		)
+		VALUES ($1, $2, COALESCE($3, false), COALESCE($4, false), COALESCE($5, false), $6, COALESCE($7, false), $8, $9)
+		ON CONFLICT (client_device_id, jid) DO UPDATE SET
+			archived = COALESCE($3, whatsmeow_extended_chat.archived),
+			pinned = COALESCE($4, whatsmeow_extended_chat.pinned),
+			muted = COALESCE($5, whatsmeow_extended_chat.muted),
+			muted_until = CASE WHEN $5::BOOLEAN IS NULL THEN whatsmeow_extended_chat.muted_until ELSE $6 END,
+			unread = COALESCE($7, whatsmeow_extended_chat.unread),
+			cleared_at = GREATEST($8, whatsmeow_extended_chat.cleared_at),
+			deleted_at = GREATEST($9, whatsmeow_extended_chat.deleted_at),
+			updated_at = now()`,
+		clientDeviceID,
+		jid,
+		s.Archived,
+		s.Pinned,
+		s.Muted,
+		s.MutedUntil,
+		s.Unread,
+		s.ClearedAt,
+		s.DeletedAt,
+	)
+	return err
+}
+
+// lastMessage is the range whatsapp applies chat actions to, up to the newest stored message of the chat.
+func (c *Client) lastMessage(clientDeviceID string, chat types.JID) (*waProto.SyncActionMessageRange, time.Time, *waProto.MessageKey, error) {
+	m, err := c.messages.LastMessage(clientDeviceID, chat.String())
+	if err != nil {
+		return nil, time.Time{}, nil, err
+	}
+	if m == nil {
+		now := time.Now()
+		return &waProto.SyncActionMessageRange{LastMessageTimestamp: proto.Int64(now.Unix())}, now, nil, nil
+	}
+
+	key := &waProto.MessageKey{
+		RemoteJID: proto.String(chat.String()),
+		FromMe:    proto.Bool(m.FromMe),
+		ID:        proto.String(m.MessageID),
+	}
+	if chat.Server != types.DefaultUserServer && !m.FromMe {
+		key.Participant = proto.String(m.SenderJID)
+	}
+	return &waProto.SyncActionMessageRange{
+		LastMessageTimestamp: proto.Int64(m.Timestamp.Unix()),
+		Messages: []*waProto.SyncActionMessage{{
+			Key:       key,
+			Timestamp: proto.Int64(m.Timestamp.Unix()),
+		}},
+	}, m.Timestamp, key, nil
+}
+
+// chatPatch builds the patches whatsmeow has no builder for. The index and version are those of the mutations
+// the phone syncs for the same action, as seen in decoded app state patches:
+//
+//	markChatAsRead  version 3  [markChatAsRead, jid]
+//	deleteChat      version 6  [deleteChat, jid, deleteMedia]
+//	clearChat       version 6  [clearChat, jid, deleteStarred, deleteMedia]
+func chatPatch(typ appstate.WAPatchName, version int32, value *waProto.SyncActionValue, index ...string) appstate.PatchInfo {
+	return appstate.PatchInfo{
+		Type: typ,
+		Mutations: []appstate.MutationInfo{{
+			Index:   index,
+			Version: version,
+			Value:   value,
+		}},
+	}
+}
+
+func buildMarkChatAsRead(chat types.JID, read bool, messageRange *waProto.SyncActionMessageRange) appstate.PatchInfo {
+	return chatPatch(appstate.WAPatchRegularLow, 3, &waProto.SyncActionValue{
+		MarkChatAsReadAction: &waProto.MarkChatAsReadAction{
+			Read:         proto.Bool(read),
+			MessageRange: messageRange,
+		},
+	}, appstate.IndexMarkChatAsRead, chat.String())
+}
+
+// buildDeleteChat deletes the media of the chat too.
+func buildDeleteChat(chat types.JID, messageRange *waProto.SyncActionMessageRange) appstate.PatchInfo {
+	return chatPatch(appstate.WAPatchRegularHigh, 6, &waProto.SyncActionValue{
+		DeleteChatAction: &waProto.DeleteChatAction{MessageRange: messageRange},
+	}, appstate.IndexDeleteChat, chat.String(), "1")
+}
+
+// buildClearChat keeps the starred messages and the media of the chat.
+func buildClearChat(chat types.JID, messageRange *waProto.SyncActionMessageRange) appstate.PatchInfo {
+	return chatPatch(appstate.WAPatchRegularHigh, 6, &waProto.SyncActionValue{
+		ClearChatAction: &waProto.ClearChatAction{MessageRange: messageRange},
+	}, appstate.IndexClearChat, chat.String(), "0", "0")
+}
+
+// buildMute is appstate.BuildMute, except that muting forever sends -1 as the end like the phone does.
+func buildMute(chat types.JID, mute bool, duration time.Duration) appstate.PatchInfo {
+	patch := appstate.BuildMute(chat, mute, duration)
+	if mute && duration == 0 {
+		patch.Mutations[0].Value.MuteAction.MuteEndTimestamp = proto.Int64(-1)
+	}
+	return patch
+}
+
+// ArchiveChat archives or unarchives the chat on every device, archiving also unpins it.
+func (c *Client) ArchiveChat(cli *whatsmeow.Client, clientDeviceID string, chat types.JID, archive bool) error {
+	chat = chat.ToNonAD()
+	_, timestamp, key, err := c.lastMessage(clientDeviceID, chat)
+	if err != nil {
+		return err
+	}
+	if err = cli.SendAppState(appstate.BuildArchive(chat, archive, timestamp, key)); err != nil {
+		return err
+	}
+	state := &chatState{Archived: &archive}
+	if archive {
+		state.Pinned = proto.Bool(false)
+	}
+	return c.messages.saveChatState(clientDeviceID, chat.String(), state)
+}
+
+func (c *Client) PinChat(cli *whatsmeow.Client, clientDeviceID string, chat types.JID, pin bool) error {
+	chat = chat.ToNonAD()
+	if err := cli.SendAppState(appstate.BuildPin(chat, pin)); err != nil {
+		return err
+	}
+	return c.messages.saveChatState(clientDeviceID, chat.String(), &chatState{Pinned: &pin})
+}
+
+// MuteChat mutes the chat for the duration, zero mutes it forever. Unmuting ignores the duration.
+func (c *Client) MuteChat(cli *whatsmeow.Client, clientDeviceID string, chat types.JID, mute bool, duration time.Duration) error {
+	if duration < 0 {
+		return ErrInvalidMuteDuration
+	}
+	chat = chat.ToNonAD()
+	if !mute {
+		duration = 0
+	}
+	if err := cli.SendAppState(buildMute(chat, mute, duration)); err != nil {
+		return err
+	}
+	state := &chatState{Muted: &mute}
+	if duration > 0 {
+		until := time.Now().Add(duration).UTC()
+		state.MutedUntil = &until
+	}
+	return c.messages.saveChatState(clientDeviceID, chat.String(), state)
+}
+
+// MarkChatUnread shows the chat as unread on the phone, or removes the mark again.
+func (c *Client) MarkChatUnread(cli *whatsmeow.Client, clientDeviceID string, chat types.JID, unread bool) error {
+	chat = chat.ToNonAD()
+	messageRange, _, _, err := c.lastMessage(clientDeviceID, chat)
+	if err != nil {
+		return err
+	}
+	if err = cli.SendAppState(buildMarkChatAsRead(chat, !unread, messageRange)); err != nil {
+		return err
+	}
+	return c.messages.saveChatState(clientDeviceID, chat.String(), &chatState{Unread: &unread})
+}
+
+// DeleteChat removes the chat from every device along with its media. The stored messages are kept.
+func (c *Client) DeleteChat(cli *whatsmeow.Client, clientDeviceID string, chat types.JID) error {
+	chat = chat.ToNonAD()
+	messageRange, _, _, err := c.lastMessage(clientDeviceID, chat)
+	if err != nil {
+		return err
+	}
+	if err = cli.SendAppState(buildDeleteChat(chat, messageRange)); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	return c.messages.saveChatState(clientDeviceID, chat.String(), &chatState{DeletedAt: &now})
+}
+
+// ClearChat removes the messages of the chat from every device but keeps the chat and its starred messages.
+// The stored messages are kept.
+func (c *Client) ClearChat(cli *whatsmeow.Client, clientDeviceID string, chat types.JID) error {
+	chat = chat.ToNonAD()
+	messageRange, _, _, err := c.lastMessage(clientDeviceID, chat)
+	if err != nil {
+		return err
+	}
+	if err = cli.SendAppState(buildClearChat(chat, messageRange)); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	return c.messages.saveChatState(clientDeviceID, chat.String(), &chatState{ClearedAt: &now})
+}
+
+// handleChatState stores the chat actions taken on the phone or another device.
+func (c *Client) handleChatState(clientDeviceID string, jid types.JID, state *chatState) {
+	if err := c.messages.saveChatState(clientDeviceID, jid.ToNonAD().String(), state); err != nil {
+		c.log.Errorf("cannot store state of chat %v for client id: %v, %v", jid, clientDeviceID, err)
+	}
+}
+
+func (c *Client) handleArchive(clientDeviceID string, evt *events.Archive) {
+	archived := evt.Action.GetArchived()
+	state := &chatState{Archived: &archived}
+	if archived {
+		state.Pinned = proto.Bool(false)
+	}
+	c.handleChatState(clientDeviceID, evt.JID, state)
+}
+
+func (c *Client) handlePin(clientDeviceID string, evt *events.Pin) {
+	c.handleChatState(clientDeviceID, evt.JID, &chatState{Pinned: proto.Bool(evt.Action.GetPinned())})
+}
+
+func (c *Client) handleMute(clientDeviceID string, evt *events.Mute) {
+	muted := evt.Action.GetMuted()
+	state := &chatState{Muted: &muted}
+	// the phone sends -1 for muted forever
+	if end := evt.Action.GetMuteEndTimestamp(); muted && end > 0 {
+		until := time.UnixMilli(end).UTC()
+		state.MutedUntil = &until
+	}
+	c.handleChatState(clientDeviceID, evt.JID, state)
+}
+
+func (c *Client) handleMarkChatAsRead(clientDeviceID string, evt *events.MarkChatAsRead) {
+	c.handleChatState(clientDeviceID, evt.JID, &chatState{Unread: proto.Bool(!evt.Action.GetRead())})
+}
+
+func (c *Client) handleDeleteChat(clientDeviceID string, evt *events.DeleteChat) {
+	deletedAt := evt.Timestamp.UTC()
+	c.handleChatState(clientDeviceID, evt.JID, &chatState{DeletedAt: &deletedAt})
+}
+
+func (c *Client) handleClearChat(clientDeviceID string, evt *events.ClearChat) {
+	clearedAt := evt.Timestamp.UTC()
+	c.handleChatState(clientDeviceID, evt.JID, &chatState{ClearedAt: &clearedAt})
+}
@@ -0,0 +1,77 @@
+package whatsapp
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mau.fi/whatsmeow/appstate"
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestChatPatches(t *testing.T) {
+	chat := types.NewJID("6281234567890", types.DefaultUserServer)
+	messageRange := &waProto.SyncActionMessageRange{LastMessageTimestamp: proto.Int64(1700000000)}
+
+	tests := []struct {
+		name    string
+		patch   appstate.PatchInfo
+		typ     appstate.WAPatchName
+		version int32
+		index   []string
+	}{
+		{
+			name:    "mark unread",
+			patch:   buildMarkChatAsRead(chat, false, messageRange),
+			typ:     appstate.WAPatchRegularLow,
+			version: 3,
+			index:   []string{appstate.IndexMarkChatAsRead, "6281234567890@s.whatsapp.net"},
+		},
+		{
+			name:    "delete",
+			patch:   buildDeleteChat(chat, messageRange),
+			typ:     appstate.WAPatchRegularHigh,
+			version: 6,
+			index:   []string{appstate.IndexDeleteChat, "6281234567890@s.whatsapp.net", "1"},
+		},
+		{
+			name:    "clear",
+			patch:   buildClearChat(chat, messageRange),
+			typ:     appstate.WAPatchRegularHigh,
+			version: 6,
+			index:   []string{appstate.IndexClearChat, "6281234567890@s.whatsapp.net", "0", "0"},
+		},
+	}
+	for _, tt := range tests {
+		if tt.patch.Type != tt.typ || len(tt.patch.Mutations) != 1 {
+			t.Errorf("%s: patch type %v with %d mutations", tt.name, tt.patch.Type, len(tt.patch.Mutations))
+			continue
+		}
+		m := tt.patch.Mutations[0]
+		if m.Version != tt.version || !reflect.DeepEqual(m.Index, tt.index) {
+			t.Errorf("%s: version %d index %q, want %d %q", tt.name, m.Version, m.Index, tt.version, tt.index)
+		}
+	}
+}
+
+func TestBuildMute(t *testing.T) {
+	chat := types.NewJID("6281234567890", types.DefaultUserServer)
+
+	forever := buildMute(chat, true, 0).Mutations[0].Value.GetMuteAction()
+	if !forever.GetMuted() || forever.MuteEndTimestamp == nil || forever.GetMuteEndTimestamp() != -1 {
+		t.Errorf("mute forever = %v", forever)
+	}
+
+	start := time.Now().Truncate(time.Millisecond)
+	hour := buildMute(chat, true, time.Hour).Mutations[0].Value.GetMuteAction()
+	if end := time.UnixMilli(hour.GetMuteEndTimestamp()); end.Before(start.Add(time.Hour)) || end.After(time.Now().Add(time.Hour)) {
+		t.Errorf("mute for an hour ends at %v", end)
+	}
+
+	unmute := buildMute(chat, false, 0).Mutations[0].Value.GetMuteAction()
+	if unmute.GetMuted() || unmute.MuteEndTimestamp != nil {
+		t.Errorf("unmute = %v", unmute)
+	}
+}
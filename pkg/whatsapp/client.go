@@ -4,13 +4,20 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"math"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/hrz8/whatsapp-api/pkg/blob"
+	"github.com/hrz8/whatsapp-api/pkg/fetch"
 	"go.mau.fi/whatsmeow"
+	waProto "go.mau.fi/whatsmeow/binary/proto"
 	"go.mau.fi/whatsmeow/store"
 	"go.mau.fi/whatsmeow/store/sqlstore"
 	"go.mau.fi/whatsmeow/types/events"
 	waLog "go.mau.fi/whatsmeow/util/log"
+	"google.golang.org/protobuf/proto"
 )
 
 const (
@@ -22,6 +29,11 @@ const (
 type EventHandler func(cli *whatsmeow.Client, clientDeviceID string) whatsmeow.EventHandler
 type Option func(c *Client)
 
+// CommandRouter receives every incoming message of every device, see pkg/bot for the implementation.
+type CommandRouter interface {
+	Route(c *Client, cli *whatsmeow.Client, clientDeviceID string, evt *events.Message) bool
+}
+
 var (
 	ErrClientAlreadyExist = errors.New("whatsapp client with specific id already exist")
 	ErrClientNotExist     = errors.New("whatsapp client is not exits")
@@ -35,15 +47,37 @@ type Client struct {
 	QR  map[string]string
 
 	// customable
-	osName     string
-	osVersion  [3]uint32
-	evtHandler EventHandler
+	osName         string
+	osVersion      [3]uint32
+	evtHandlers    []EventHandler
+	routers        []CommandRouter
+	optOutKeywords []string
+	historySync    bool
+	historyMaxAge  time.Duration
+	blobs          blob.Store
+	mediaDownload  MediaDownloadConfig
+	fetcher        *fetch.Fetcher
+	contactCache   *ContactCache
+	defaultCountry string
 
 	// default
-	container *sqlstore.Container
-	mig       *Migration
-	repo      *DeviceRepo
-	log       waLog.Logger
+	container    *sqlstore.Container
+	mig          *Migration
+	repo         *DeviceRepo
+	templates    *TemplateRepo
+	suppressions *SuppressionRepo
+	messages     *MessageRepo
+	historySyncs *HistorySyncRepo
+	media        *MediaRepo
+	contacts     *ContactRepo
+	settings     *SettingsRepo
+	receipts     *ReceiptRepo
+	pictures     *pictureCache
+	presences    *presenceTracker
+	handled      *handledMessages
+	mediaSem     chan struct{}
+	events       *Broker
+	log          waLog.Logger
 }
 
 func WithOsInfo(name string, version [3]uint32) Option {
@@ -55,7 +89,57 @@ func WithOsInfo(name string, version [3]uint32) Option {
 
 func WithEventHandler(handler EventHandler) Option {
 	return func(c *Client) {
-		c.evtHandler = handler
+		c.evtHandlers = append(c.evtHandlers, handler)
+	}
+}
+
+func WithCommandRouter(router CommandRouter) Option {
+	return func(c *Client) {
+		c.routers = append(c.routers, router)
+	}
+}
+
+// WithHistorySync toggles importing the history sync of newly paired devices,
+// a positive maxAge skips messages older than that and asks the phone to only sync that many days.
+func WithHistorySync(enabled bool, maxAge time.Duration) Option {
+	return func(c *Client) {
+		c.historySync = enabled
+		c.historyMaxAge = maxAge
+	}
+}
+
+// WithMediaStore keeps media in the given blob store and automatically downloads incoming media matching cfg.
+func WithMediaStore(store blob.Store, cfg MediaDownloadConfig) Option {
+	return func(c *Client) {
+		c.blobs = store
+		c.mediaDownload = cfg
+	}
+}
+
+// WithMediaFetcher sets how media given by url is downloaded before being sent, e.g. to restrict the hosts.
+func WithMediaFetcher(fetcher *fetch.Fetcher) Option {
+	return func(c *Client) {
+		c.fetcher = fetcher
+	}
+}
+
+// WithContactCache sets how long the registration status of a number is cached and whether postgres keeps it.
+func WithContactCache(cfg ContactCacheConfig) Option {
+	return func(c *Client) {
+		c.contactCache = NewContactCache(cfg)
+	}
+}
+
+// WithDefaultCountry completes national numbers of devices without their own default country code.
+func WithDefaultCountry(cc string) Option {
+	return func(c *Client) {
+		c.defaultCountry = strings.TrimPrefix(cc, "+")
+	}
+}
+
+func WithOptOutKeywords(keywords ...string) Option {
+	return func(c *Client) {
+		c.optOutKeywords = keywords
 	}
 }
 
@@ -72,15 +156,33 @@ func NewClient(db *sql.DB, opts ...Option) *Client {
 		QR: qr,
 
 		// customable
-		osName:     "Whatsapp",
-		osVersion:  [3]uint32{0, 1, 0},
-		evtHandler: nil,
+		osName:         "Whatsapp",
+		osVersion:      [3]uint32{0, 1, 0},
+		evtHandlers:    nil,
+		optOutKeywords: DefaultOptOutKeywords,
+		historySync:    true,
+		historyMaxAge:  0,
+		fetcher:        fetch.New(fetch.Config{}),
+		contactCache:   NewContactCache(DefaultContactCacheConfig),
 
 		// default
-		container: sqlstore.NewWithDB(db, "postgres", dbLog),
-		mig:       &Migration{db, dbLog},
-		repo:      &DeviceRepo{db},
-		log:       log,
+		container:    sqlstore.NewWithDB(db, "postgres", dbLog),
+		mig:          &Migration{db, dbLog},
+		repo:         &DeviceRepo{db},
+		templates:    &TemplateRepo{db},
+		suppressions: &SuppressionRepo{db},
+		messages:     &MessageRepo{db},
+		historySyncs: &HistorySyncRepo{db},
+		media:        &MediaRepo{db},
+		contacts:     &ContactRepo{db},
+		settings:     &SettingsRepo{db},
+		receipts:     &ReceiptRepo{db},
+		pictures:     newPictureCache(),
+		presences:    newPresenceTracker(),
+		handled:      newHandledMessages(),
+		mediaSem:     make(chan struct{}, 4),
+		events:       NewBroker(),
+		log:          log,
 	}
 
 	for _, opt := range opts {
@@ -88,6 +190,12 @@ func NewClient(db *sql.DB, opts ...Option) *Client {
 	}
 
 	store.SetOSInfo(waCli.osName, waCli.osVersion)
+	if waCli.historyMaxAge > 0 {
+		days := uint32(math.Ceil(waCli.historyMaxAge.Hours() / 24))
+		store.DeviceProps.HistorySyncConfig = &waProto.DeviceProps_HistorySyncConfig{
+			FullSyncDaysLimit: proto.Uint32(days),
+		}
+	}
 	return waCli
 }
 
@@ -100,9 +208,13 @@ func (c *Client) NewMeow(clientDeviceID string) *whatsmeow.Client {
 func (c *Client) initMeow(device *store.Device, clientDeviceID string) *whatsmeow.Client {
 	cliLog := waLog.Stdout("Device-"+clientDeviceID, LogLevelDevice, true)
 	cli := whatsmeow.NewClient(device, cliLog)
-	cli.AddEventHandler(c.defaultEventHandler(clientDeviceID))
-	if c.evtHandler != nil {
-		cli.AddEventHandler(c.evtHandler(cli, clientDeviceID))
+	cli.AddEventHandler(c.defaultEventHandler(cli, clientDeviceID))
+	// routers go first, so the other handlers can tell which messages were commands, see Handled
+	for _, router := range c.routers {
+		cli.AddEventHandler(c.routerEventHandler(router, cli, clientDeviceID))
+	}
+	for _, evtHandler := range c.evtHandlers {
+		cli.AddEventHandler(evtHandler(cli, clientDeviceID))
 	}
 
 	return cli
@@ -121,6 +233,36 @@ func (c *Client) Upgrade() (err error) {
 	return
 }
 
+// AddEventHandler registers an event handler after the client is created,
+// it only applies to whatsapp clients initialized afterwards (e.g. by Restore or NewMeow).
+func (c *Client) AddEventHandler(handler EventHandler) {
+	c.evtHandlers = append(c.evtHandlers, handler)
+}
+
+func (c *Client) Templates() *TemplateRepo {
+	return c.templates
+}
+
+func (c *Client) Suppressions() *SuppressionRepo {
+	return c.suppressions
+}
+
+func (c *Client) Messages() *MessageRepo {
+	return c.messages
+}
+
+func (c *Client) HistorySyncs() *HistorySyncRepo {
+	return c.historySyncs
+}
+
+func (c *Client) Settings() *SettingsRepo {
+	return c.settings
+}
+
+func (c *Client) Events() *Broker {
+	return c.events
+}
+
 func (c *Client) GetQR(clientDeviceID string) string {
 	c.mut.RLock()
 	defer c.mut.RUnlock()
@@ -168,6 +310,15 @@ func (c *Client) Get(clientDeviceID string) *whatsmeow.Client {
 	return cli
 }
 
+// LoggedIn returns the whatsapp client of the device, or ErrNotLogin when it is missing or not logged in.
+func (c *Client) LoggedIn(clientDeviceID string) (*whatsmeow.Client, error) {
+	cli := c.Get(clientDeviceID)
+	if cli == nil || !cli.IsLoggedIn() {
+		return nil, ErrNotLogin
+	}
+	return cli, nil
+}
+
 func (c *Client) Set(clientDeviceID string, cli *whatsmeow.Client) error {
 	curr := c.Get(clientDeviceID)
 	if curr != nil {
@@ -209,15 +360,61 @@ func (c *Client) Backup() {
 	c.log.Infof("backup done!")
 }
 
-func (c *Client) defaultEventHandler(clientDeviceID string) whatsmeow.EventHandler {
+func (c *Client) defaultEventHandler(cli *whatsmeow.Client, clientDeviceID string) whatsmeow.EventHandler {
 	return func(evt interface{}) {
-		switch evt.(type) {
+		switch v := evt.(type) {
 		case *events.PairSuccess:
 			c.ResetQR(clientDeviceID)
+		case *events.Message:
+			c.storeMessage(cli, clientDeviceID, v)
+			c.handleOptOut(clientDeviceID, v)
+		case *events.HistorySync:
+			c.handleHistorySync(cli, clientDeviceID, v)
+		case *events.GroupInfo:
+			c.handleGroupInfo(clientDeviceID, v)
+		case *events.JoinedGroup:
+			c.handleJoinedGroup(clientDeviceID, v)
+		case *events.Connected:
+			// a new connection starts unavailable
+			c.presences.setAvailable(clientDeviceID, false)
+			go c.resubscribePresence(cli, clientDeviceID)
+		case *events.Presence:
+			c.handlePresence(clientDeviceID, v)
+		case *events.Receipt:
+			c.handleReceipt(clientDeviceID, v)
+		case *events.Archive:
+			c.handleArchive(clientDeviceID, v)
+		case *events.Pin:
+			c.handlePin(clientDeviceID, v)
+		case *events.Mute:
+			c.handleMute(clientDeviceID, v)
+		case *events.MarkChatAsRead:
+			c.handleMarkChatAsRead(clientDeviceID, v)
+		case *events.DeleteChat:
+			c.handleDeleteChat(clientDeviceID, v)
+		case *events.ClearChat:
+			c.handleClearChat(clientDeviceID, v)
+		}
+	}
+}
+
+func (c *Client) routerEventHandler(router CommandRouter, cli *whatsmeow.Client, clientDeviceID string) whatsmeow.EventHandler {
+	return func(evt interface{}) {
+		switch v := evt.(type) {
+		case *events.Message:
+			if router.Route(c, cli, clientDeviceID, v) {
+				c.handled.add(clientDeviceID, v)
+			}
 		}
 	}
 }
 
+// Handled reports whether a command router answered the message, handlers registered through
+// WithEventHandler or AddEventHandler use it to leave commands alone.
+func (c *Client) Handled(clientDeviceID string, evt *events.Message) bool {
+	return c.handled.has(clientDeviceID, evt)
+}
+
 func (c *Client) Restore() {
 	c.log.Infof("attempting to restoring whatsapp clients connections...")
 	meowDevices, err := c.container.GetAllDevices()
@@ -0,0 +1,61 @@
+package whatsapp
+
+import (
+	"errors"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+var (
+	ErrNotCommunity          = errors.New("group is not a community")
+	ErrNoAnnouncementGroup   = errors.New("community has no announcement group")
+	ErrInvalidCommunityGroup = errors.New("a community cannot be linked as a sub group")
+)
+
+type CommunityGroup struct {
+	JID               string `json:"jid"`
+	Name              string `json:"name"`
+	IsDefaultSubGroup bool   `json:"is_default_sub_group"`
+}
+
+func NewCommunityGroups(targets []*types.GroupLinkTarget) []CommunityGroup {
+	groups := make([]CommunityGroup, 0, len(targets))
+	for _, t := range targets {
+		groups = append(groups, CommunityGroup{
+			JID:               t.JID.String(),
+			Name:              t.Name,
+			IsDefaultSubGroup: t.IsDefaultSubGroup,
+		})
+	}
+	return groups
+}
+
+// Community returns the info of the given community, failing with ErrNotCommunity for a plain group.
+func Community(cli *whatsmeow.Client, jid types.JID) (*types.GroupInfo, error) {
+	info, err := cli.GetGroupInfo(jid)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsParent {
+		return nil, ErrNotCommunity
+	}
+	return info, nil
+}
+
+// AnnouncementGroup returns the default sub group of the community, where announcements to every member go.
+func AnnouncementGroup(cli *whatsmeow.Client, community types.JID) (types.JID, error) {
+	if _, err := Community(cli, community); err != nil {
+		return types.EmptyJID, err
+	}
+	groups, err := cli.GetSubGroups(community)
+	if err != nil {
+		return types.EmptyJID, err
+	}
+	for _, g := range groups {
+		if g.IsDefaultSubGroup {
+			return g.JID, nil
+		}
+	}
+	return types.EmptyJID, ErrNoAnnouncementGroup
+}
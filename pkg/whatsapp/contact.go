@@ -0,0 +1,284 @@
+package whatsapp
+
+import (
+	"cmp"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hrz8/whatsapp-api/pkg/phone"
+	"go.mau.fi/whatsmeow"
+)
+
+// MaxContactCheck bounds a single check, every number of it goes to whatsapp in one query.
+const MaxContactCheck = 500
+
+type ContactStatus string
+
+const (
+	ContactRegistered    ContactStatus = "registered"
+	ContactNotRegistered ContactStatus = "not_registered"
+	ContactInvalid       ContactStatus = "invalid"
+)
+
+var (
+	ErrNoContacts      = errors.New("at least one phone number is required")
+	ErrTooManyContacts = fmt.Errorf("at most %d phone numbers can be checked at once", MaxContactCheck)
+	ErrNoCacheTarget   = errors.New("phones or all=true is required to invalidate the contact cache")
+)
+
+// ContactCheck is the registration status of a phone number, invalid ones only have the Reason.
+type ContactCheck struct {
+	Phone        string        `json:"phone"`
+	Number       string        `json:"number,omitempty"`
+	Status       ContactStatus `json:"status"`
+	Reason       phone.Reason  `json:"reason,omitempty"`
+	JID          string        `json:"jid,omitempty"`
+	IsBusiness   bool          `json:"is_business"`
+	BusinessName string        `json:"business_name,omitempty"`
+	Cached       bool          `json:"cached"`
+	CheckedAt    *time.Time    `json:"checked_at,omitempty"`
+}
+
+// ContactRepo caches the registration status by number, it does not depend on the device asking.
+type ContactRepo struct {
+	db *sql.DB
+}
+
+// Get returns the cached checks of the numbers done after since, keyed by number.
+func (r *ContactRepo) Get(numbers []string, since time.Time) (map[string]*ContactCheck, error) {
+	rows, err := r.db.Query(`SELECT number, is_in, jid, is_business, business_name, checked_at
+		FROM whatsmeow_extended_contact_check
+		WHERE number = ANY($1) AND checked_at > $2`,
+		numbers,
+		since,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	checks := make(map[string]*ContactCheck, len(numbers))
+	for rows.Next() {
+		var (
+			i    ContactCheck
+			isIn bool
+			at   time.Time
+		)
+		if err := rows.Scan(&i.Number, &isIn, &i.JID, &i.IsBusiness, &i.BusinessName, &at); err != nil {
+			return nil, err
+		}
+		i.Status = ContactNotRegistered
+		if isIn {
+			i.Status = ContactRegistered
+		}
+		i.Cached = true
+		i.CheckedAt = &at
+		checks[i.Number] = &i
+	}
+	return checks, rows.Err()
+}
+
+// Delete removes the checks of the numbers, or every check without numbers.
+func (r *ContactRepo) Delete(numbers []string) error {
+	if len(numbers) == 0 {
+		_, err := r.db.Exec("DELETE FROM whatsmeow_extended_contact_check")
+		return err
+	}
+	_, err := r.db.Exec("DELETE FROM whatsmeow_extended_contact_check WHERE number = ANY($1)", numbers)
+	return err
+}
+
+func (r *ContactRepo) Save(checks []*ContactCheck) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, i := range checks {
+		_, err = tx.Exec(`INSERT INTO
+			whatsmeow_extended_contact_check (
+				number,
+				is_in,
+				jid,
+				is_business,
+				business_name,
+				checked_at
+			)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (number) DO UPDATE SET
+				is_in = EXCLUDED.is_in,
+				jid = EXCLUDED.jid,
+				is_business = EXCLUDED.is_business,
+				business_name = EXCLUDED.business_name,
+				checked_at = EXCLUDED.checked_at`,
+			i.Number,
+			i.Status == ContactRegistered,
+			i.JID,
+			i.IsBusiness,
+			i.BusinessName,
+			i.CheckedAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// CheckContacts returns the registration status of every phone, in the given order, national numbers are completed with defaultCountry.
+// Numbers checked within the TTL come from the memory or postgres cache, the others are looked up together in one query.
+func (c *Client) CheckContacts(cli *whatsmeow.Client, phones []string, defaultCountry string) ([]*ContactCheck, error) {
+	if len(phones) == 0 {
+		return nil, ErrNoContacts
+	}
+	if len(phones) > MaxContactCheck {
+		return nil, ErrTooManyContacts
+	}
+
+	results := make([]*ContactCheck, len(phones))
+	numbers := make([]string, 0, len(phones))
+	seen := make(map[string]bool, len(phones))
+	for i, p := range phones {
+		results[i] = &ContactCheck{Phone: p, Status: ContactInvalid}
+		rcpt, err := ParseRecipient(RecipientPhone, p, defaultCountry)
+		if err != nil {
+			var perr *phone.Error
+			if errors.As(err, &perr) {
+				results[i].Reason = perr.Reason
+			}
+			continue
+		}
+		results[i].Number = rcpt.JID.User
+		if !seen[rcpt.JID.User] {
+			seen[rcpt.JID.User] = true
+			numbers = append(numbers, rcpt.JID.User)
+		}
+	}
+
+	now := time.Now().UTC()
+	cache := c.contactCache
+	checks := make(map[string]*ContactCheck, len(numbers))
+	missing := make([]string, 0, len(numbers))
+	for _, number := range numbers {
+		if check := cache.get(number, now); check != nil {
+			checks[number] = check
+			continue
+		}
+		missing = append(missing, number)
+	}
+
+	persistentHits := 0
+	if cache.Enabled() && cache.cfg.Persist && len(missing) > 0 {
+		stored, err := c.contacts.Get(missing, now.Add(-cache.cfg.TTL))
+		if err != nil {
+			c.log.Errorf("cannot read contact check cache, %v", err)
+		}
+		fresh := make([]*ContactCheck, 0, len(stored))
+		for _, check := range stored {
+			if !cache.expired(check, now) {
+				checks[check.Number] = check
+				fresh = append(fresh, check)
+			}
+		}
+		cache.set(fresh, now)
+		persistentHits = len(fresh)
+	}
+
+	queries := make([]string, 0, len(missing))
+	for _, number := range missing {
+		if checks[number] == nil {
+			queries = append(queries, "+"+number)
+		}
+	}
+	cache.count(persistentHits, len(queries))
+	if len(queries) > 0 {
+		resp, err := cli.IsOnWhatsApp(queries)
+		if err != nil {
+			return nil, err
+		}
+
+		fresh := make([]*ContactCheck, 0, len(queries))
+		for _, info := range resp {
+			check := &ContactCheck{
+				Number:    strings.TrimPrefix(info.Query, "+"),
+				Status:    ContactNotRegistered,
+				CheckedAt: &now,
+			}
+			if info.IsIn {
+				check.Status = ContactRegistered
+				check.JID = info.JID.ToNonAD().String()
+			}
+			if info.VerifiedName != nil {
+				check.IsBusiness = true
+				check.BusinessName = info.VerifiedName.Details.GetVerifiedName()
+			}
+			checks[check.Number] = check
+			fresh = append(fresh, check)
+		}
+		// whatsapp leaves out numbers it does not know at all
+		for _, query := range queries {
+			number := strings.TrimPrefix(query, "+")
+			if checks[number] == nil {
+				checks[number] = &ContactCheck{Number: number, Status: ContactNotRegistered, CheckedAt: &now}
+				fresh = append(fresh, checks[number])
+			}
+		}
+
+		if cache.Enabled() {
+			cache.set(fresh, now)
+			if cache.cfg.Persist {
+				if err = c.contacts.Save(fresh); err != nil {
+					c.log.Errorf("cannot write contact check cache, %v", err)
+				}
+			}
+		}
+	}
+
+	for _, result := range results {
+		check := checks[result.Number]
+		if check == nil {
+			continue
+		}
+		phone := result.Phone
+		*result = *check
+		result.Phone = phone
+	}
+	return results, nil
+}
+
+func (c *Client) ContactCache() *ContactCache {
+	return c.contactCache
+}
+
+// InvalidateContacts forgets the cached checks of the phones, or every cached check when all is set.
+// Invalid phones are ignored unless none of them is valid.
+func (c *Client) InvalidateContacts(phones []string, all bool, defaultCountry string) error {
+	if !all && len(phones) == 0 {
+		return ErrNoCacheTarget
+	}
+	numbers := make([]string, 0, len(phones))
+	if !all {
+		var firstErr error
+		for _, p := range phones {
+			rcpt, err := ParseRecipient(RecipientPhone, p, defaultCountry)
+			if err != nil {
+				firstErr = cmp.Or(firstErr, err)
+				continue
+			}
+			numbers = append(numbers, rcpt.JID.User)
+		}
+		if len(numbers) == 0 {
+			return firstErr
+		}
+	}
+
+	c.contactCache.Invalidate(numbers...)
+	if c.contactCache.cfg.Persist {
+		return c.contacts.Delete(numbers)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package whatsapp
+
+import (
+	"sync"
+	"time"
+)
+
+// contactCacheSweep is the size after which expired entries are swept on insert.
+const contactCacheSweep = 100_000
+
+// ContactCacheConfig sets how long checks are trusted, numbers that are not registered can have a shorter TTL
+// since they may register any time. Persist keeps the checks in postgres so they survive restarts.
+// A zero TTL disables the cache.
+type ContactCacheConfig struct {
+	TTL         time.Duration
+	NegativeTTL time.Duration
+	Persist     bool
+}
+
+var DefaultContactCacheConfig = ContactCacheConfig{
+	TTL:         24 * time.Hour,
+	NegativeTTL: time.Hour,
+	Persist:     true,
+}
+
+// ContactCacheStats counts checked numbers by where their answer came from.
+type ContactCacheStats struct {
+	Entries            int     `json:"entries"`
+	MemoryHits         uint64  `json:"memory_hits"`
+	PersistentHits     uint64  `json:"persistent_hits"`
+	Misses             uint64  `json:"misses"`
+	HitRate            float64 `json:"hit_rate"`
+	TTLSeconds         int     `json:"ttl_seconds"`
+	NegativeTTLSeconds int     `json:"negative_ttl_seconds"`
+	Persistent         bool    `json:"persistent"`
+}
+
+// ContactCache keeps contact checks in memory in front of the postgres cache, it is shared by every device.
+type ContactCache struct {
+	mut     sync.Mutex
+	cfg     ContactCacheConfig
+	entries map[string]*ContactCheck
+
+	memoryHits     uint64
+	persistentHits uint64
+	misses         uint64
+}
+
+func NewContactCache(cfg ContactCacheConfig) *ContactCache {
+	if cfg.NegativeTTL <= 0 || cfg.NegativeTTL > cfg.TTL {
+		cfg.NegativeTTL = cfg.TTL
+	}
+	return &ContactCache{cfg: cfg, entries: make(map[string]*ContactCheck)}
+}
+
+func (c *ContactCache) Enabled() bool {
+	return c.cfg.TTL > 0
+}
+
+func (c *ContactCache) expired(check *ContactCheck, now time.Time) bool {
+	ttl := c.cfg.TTL
+	if check.Status != ContactRegistered {
+		ttl = c.cfg.NegativeTTL
+	}
+	return check.CheckedAt == nil || now.Sub(*check.CheckedAt) >= ttl
+}
+
+func (c *ContactCache) get(number string, now time.Time) *ContactCheck {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	check := c.entries[number]
+	if check == nil {
+		return nil
+	}
+	if c.expired(check, now) {
+		delete(c.entries, number)
+		return nil
+	}
+	c.memoryHits++
+	cached := *check
+	cached.Cached = true
+	return &cached
+}
+
+func (c *ContactCache) set(checks []*ContactCheck, now time.Time) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if len(c.entries) >= contactCacheSweep {
+		for number, check := range c.entries {
+			if c.expired(check, now) {
+				delete(c.entries, number)
+			}
+		}
+	}
+	for _, check := range checks {
+		entry := *check
+		c.entries[check.Number] = &entry
+	}
+}
+
+func (c *ContactCache) count(persistentHits int, misses int) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.persistentHits += uint64(persistentHits)
+	c.misses += uint64(misses)
+}
+
+// Invalidate drops the cached checks of the numbers, or every check without numbers.
+func (c *ContactCache) Invalidate(numbers ...string) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if len(numbers) == 0 {
+		c.entries = make(map[string]*ContactCheck)
+		return
+	}
+	for _, number := range numbers {
+		delete(c.entries, number)
+	}
+}
+
+func (c *ContactCache) Stats() *ContactCacheStats {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	stats := &ContactCacheStats{
+		Entries:            len(c.entries),
+		MemoryHits:         c.memoryHits,
+		PersistentHits:     c.persistentHits,
+		Misses:             c.misses,
+		TTLSeconds:         int(c.cfg.TTL.Seconds()),
+		NegativeTTLSeconds: int(c.cfg.NegativeTTL.Seconds()),
+		Persistent:         c.cfg.Persist,
+	}
+	if total := c.memoryHits + c.persistentHits + c.misses; total > 0 {
+		stats.HitRate = float64(c.memoryHits+c.persistentHits) / float64(total)
+	}
+	return stats
+}
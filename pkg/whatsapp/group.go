@@ -0,0 +1,197 @@
+package whatsapp
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/hrz8/whatsapp-api/pkg/phone"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// MaxGroupNameLength is enforced by whatsapp, longer names are rejected.
+const MaxGroupNameLength = 100
+
+const (
+	EventGroupJoined       = "group.joined"
+	EventGroupParticipants = "group.participants"
+)
+
+var (
+	ErrInvalidGroup         = errors.New("group jid is invalid")
+	ErrInvalidParticipant   = errors.New("participant is invalid")
+	ErrInvalidGroupName     = errors.New("group name must be between 1 and 100 characters")
+	ErrInvalidGroupAction   = errors.New("participant action must be one of add, remove, promote or demote")
+	ErrInvalidRequestAction = errors.New("join request action must be either approve or reject")
+)
+
+type GroupParticipant struct {
+	JID          string `json:"jid"`
+	IsAdmin      bool   `json:"is_admin"`
+	IsSuperAdmin bool   `json:"is_super_admin"`
+	// whatsapp error code when a participant change failed, e.g. 403 when the user's privacy settings forbid adding them
+	Error int `json:"error,omitempty"`
+}
+
+type Group struct {
+	JID               string             `json:"jid"`
+	Name              string             `json:"name"`
+	Topic             string             `json:"topic"`
+	OwnerJID          string             `json:"owner_jid"`
+	IsAnnounce        bool               `json:"is_announce"`
+	IsLocked          bool               `json:"is_locked"`
+	IsEphemeral       bool               `json:"is_ephemeral"`
+	DisappearingTimer uint32             `json:"disappearing_timer"`
+	IsParent          bool               `json:"is_parent"`
+	LinkedParentJID   string             `json:"linked_parent_jid,omitempty"`
+	IsDefaultSubGroup bool               `json:"is_default_sub_group"`
+	Participants      []GroupParticipant `json:"participants"`
+	CreatedAt         time.Time          `json:"created_at"`
+}
+
+func jidString(jid types.JID) string {
+	if jid.IsEmpty() {
+		return ""
+	}
+	return jid.String()
+}
+
+func NewGroupParticipants(participants []types.GroupParticipant) []GroupParticipant {
+	result := make([]GroupParticipant, 0, len(participants))
+	for _, p := range participants {
+		result = append(result, GroupParticipant{
+			JID:          p.JID.String(),
+			IsAdmin:      p.IsAdmin,
+			IsSuperAdmin: p.IsSuperAdmin,
+			Error:        p.Error,
+		})
+	}
+	return result
+}
+
+func NewGroup(info *types.GroupInfo) *Group {
+	return &Group{
+		JID:               info.JID.String(),
+		Name:              info.Name,
+		Topic:             info.Topic,
+		OwnerJID:          jidString(info.OwnerJID),
+		IsAnnounce:        info.IsAnnounce,
+		IsLocked:          info.IsLocked,
+		IsEphemeral:       info.IsEphemeral,
+		DisappearingTimer: info.DisappearingTimer,
+		IsParent:          info.IsParent,
+		LinkedParentJID:   jidString(info.LinkedParentJID),
+		IsDefaultSubGroup: info.IsDefaultSubGroup,
+		Participants:      NewGroupParticipants(info.Participants),
+		CreatedAt:         info.GroupCreated,
+	}
+}
+
+// ParseGroupJID accepts a full group JID or only its user part.
+func ParseGroupJID(arg string) (types.JID, error) {
+	arg = strings.TrimSpace(arg)
+	if arg == "" {
+		return types.EmptyJID, ErrInvalidGroup
+	}
+	if !strings.ContainsRune(arg, '@') {
+		arg += "@" + types.GroupServer
+	}
+	jid, err := types.ParseJID(arg)
+	if err != nil || jid.Server != types.GroupServer || jid.User == "" {
+		return types.EmptyJID, ErrInvalidGroup
+	}
+	return jid, nil
+}
+
+// ParseParticipants turns phone numbers or user JIDs into JIDs, failing on the first invalid one.
+// National numbers are completed with defaultCountry.
+func ParseParticipants(args []string, defaultCountry string) ([]types.JID, error) {
+	jids := make([]types.JID, 0, len(args))
+	for _, arg := range args {
+		rcpt, err := ParseRecipient(RecipientPhone, arg, defaultCountry)
+		if errors.Is(err, phone.ErrInvalid) {
+			return nil, err
+		}
+		if err != nil {
+			return nil, ErrInvalidParticipant
+		}
+		jids = append(jids, rcpt.JID)
+	}
+	return jids, nil
+}
+
+// ParseParticipantAction validates the participant change of UpdateGroupParticipants.
+func ParseParticipantAction(action string) (whatsmeow.ParticipantChange, error) {
+	switch change := whatsmeow.ParticipantChange(action); change {
+	case whatsmeow.ParticipantChangeAdd, whatsmeow.ParticipantChangeRemove,
+		whatsmeow.ParticipantChangePromote, whatsmeow.ParticipantChangeDemote:
+		return change, nil
+	}
+	return "", ErrInvalidGroupAction
+}
+
+// ParseRequestAction validates the join request change of UpdateGroupRequestParticipants.
+func ParseRequestAction(action string) (whatsmeow.ParticipantRequestChange, error) {
+	switch change := whatsmeow.ParticipantRequestChange(action); change {
+	case whatsmeow.ParticipantChangeApprove, whatsmeow.ParticipantChangeReject:
+		return change, nil
+	}
+	return "", ErrInvalidRequestAction
+}
+
+func ValidateGroupName(name string) error {
+	if n := len([]rune(strings.TrimSpace(name))); n == 0 || n > MaxGroupNameLength {
+		return ErrInvalidGroupName
+	}
+	return nil
+}
+
+type GroupParticipantsEvent struct {
+	GroupJID   string   `json:"group_jid"`
+	SenderJID  string   `json:"sender_jid,omitempty"`
+	JoinReason string   `json:"join_reason,omitempty"`
+	Joined     []string `json:"joined,omitempty"`
+	Left       []string `json:"left,omitempty"`
+	Promoted   []string `json:"promoted,omitempty"`
+	Demoted    []string `json:"demoted,omitempty"`
+}
+
+func jidStrings(jids []types.JID) []string {
+	if len(jids) == 0 {
+		return nil
+	}
+	result := make([]string, len(jids))
+	for i, jid := range jids {
+		result[i] = jid.String()
+	}
+	return result
+}
+
+func (c *Client) handleGroupInfo(clientDeviceID string, evt *events.GroupInfo) {
+	if len(evt.Join)+len(evt.Leave)+len(evt.Promote)+len(evt.Demote) == 0 {
+		return
+	}
+	data := &GroupParticipantsEvent{
+		GroupJID:   evt.JID.String(),
+		JoinReason: evt.JoinReason,
+		Joined:     jidStrings(evt.Join),
+		Left:       jidStrings(evt.Leave),
+		Promoted:   jidStrings(evt.Promote),
+		Demoted:    jidStrings(evt.Demote),
+	}
+	if evt.Sender != nil {
+		data.SenderJID = evt.Sender.String()
+	}
+	c.publish(clientDeviceID, EventGroupParticipants, data)
+}
+
+func (c *Client) handleJoinedGroup(clientDeviceID string, evt *events.JoinedGroup) {
+	group := NewGroup(&evt.GroupInfo)
+	c.publish(clientDeviceID, EventGroupJoined, map[string]any{
+		"reason": evt.Reason,
+		"type":   evt.Type,
+		"group":  group,
+	})
+}
@@ -0,0 +1,47 @@
+package whatsapp
+
+import (
+	"sync"
+	"time"
+
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// handledTTL only has to outlive the handlers of a single event, whatsmeow runs them one after another.
+const handledTTL = time.Minute
+
+// handledMessages remembers the messages command routers answered.
+type handledMessages struct {
+	mut      sync.Mutex
+	messages map[string]time.Time
+}
+
+func newHandledMessages() *handledMessages {
+	return &handledMessages{messages: make(map[string]time.Time)}
+}
+
+func handledKey(clientDeviceID string, evt *events.Message) string {
+	return clientDeviceID + "|" + evt.Info.Chat.String() + "|" + evt.Info.ID
+}
+
+func (h *handledMessages) add(clientDeviceID string, evt *events.Message) {
+	h.mut.Lock()
+	defer h.mut.Unlock()
+
+	now := time.Now()
+	h.messages[handledKey(clientDeviceID, evt)] = now
+	if len(h.messages) > 1024 {
+		for k, at := range h.messages {
+			if now.Sub(at) > handledTTL {
+				delete(h.messages, k)
+			}
+		}
+	}
+}
+
+func (h *handledMessages) has(clientDeviceID string, evt *events.Message) bool {
+	h.mut.Lock()
+	defer h.mut.Unlock()
+	at, ok := h.messages[handledKey(clientDeviceID, evt)]
+	return ok && time.Since(at) <= handledTTL
+}
@@ -0,0 +1,151 @@
+package whatsapp
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+var ErrHistorySyncNotFound = errors.New("no history sync received for device")
+
+// HistorySyncProgress accumulates the history sync chunks a device received since it paired.
+type HistorySyncProgress struct {
+	ClientDeviceID string    `json:"client_device_id"`
+	SyncType       string    `json:"sync_type"`
+	Progress       int       `json:"progress"`
+	Chunks         int       `json:"chunks"`
+	Conversations  int       `json:"conversations"`
+	Imported       int       `json:"imported"`
+	Duplicates     int       `json:"duplicates"`
+	Skipped        int       `json:"skipped"`
+	StartedAt      time.Time `json:"started_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type HistorySyncRepo struct {
+	db *sql.DB
+}
+
+// Record adds the counters of a chunk to the device progress.
+func (r *HistorySyncRepo) Record(p *HistorySyncProgress) error {
+	_, err := r.db.Exec(`INSERT INTO
+		whatsmeow_extended_history_sync (
+			client_device_id,
+			sync_type,
+			progress,
+			chunks,
+			conversations,
+			imported,
+			duplicates,
+			skipped
+		)
+		VALUES ($1, $2, $3, 1, $4, $5, $6, $7)
+		ON CONFLICT (client_device_id) DO UPDATE SET
+			sync_type = EXCLUDED.sync_type,
+			progress = GREATEST(whatsmeow_extended_history_sync.progress, EXCLUDED.progress),
+			chunks = whatsmeow_extended_history_sync.chunks + 1,
+			conversations = whatsmeow_extended_history_sync.conversations + EXCLUDED.conversations,
+			imported = whatsmeow_extended_history_sync.imported + EXCLUDED.imported,
+			duplicates = whatsmeow_extended_history_sync.duplicates + EXCLUDED.duplicates,
+			skipped = whatsmeow_extended_history_sync.skipped + EXCLUDED.skipped,
+			updated_at = now()`,
+		p.ClientDeviceID,
+		p.SyncType,
+		p.Progress,
+		p.Conversations,
+		p.Imported,
+		p.Duplicates,
+		p.Skipped,
+	)
+	return err
+}
+
+func (r *HistorySyncRepo) Get(clientDeviceID string) (*HistorySyncProgress, error) {
+	row := r.db.QueryRow(`SELECT client_device_id, sync_type, progress, chunks, conversations, imported, duplicates, skipped, started_at, updated_at
+		FROM whatsmeow_extended_history_sync
+		WHERE client_device_id = $1`,
+		clientDeviceID,
+	)
+	var i HistorySyncProgress
+	err := row.Scan(
+		&i.ClientDeviceID,
+		&i.SyncType,
+		&i.Progress,
+		&i.Chunks,
+		&i.Conversations,
+		&i.Imported,
+		&i.Duplicates,
+		&i.Skipped,
+		&i.StartedAt,
+		&i.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrHistorySyncNotFound
+	}
+	return &i, err
+}
+
+func (c *Client) handleHistorySync(cli *whatsmeow.Client, clientDeviceID string, evt *events.HistorySync) {
+	if !c.historySync {
+		return
+	}
+
+	var cutoff time.Time
+	if c.historyMaxAge > 0 {
+		cutoff = time.Now().Add(-c.historyMaxAge)
+	}
+
+	p := &HistorySyncProgress{
+		ClientDeviceID: clientDeviceID,
+		SyncType:       evt.Data.GetSyncType().String(),
+		Progress:       int(evt.Data.GetProgress()),
+	}
+	for _, conv := range evt.Data.GetConversations() {
+		chatJID, err := types.ParseJID(conv.GetID())
+		if err != nil {
+			c.log.Warnf("skipping history of invalid chat %v for client id: %v", conv.GetID(), clientDeviceID)
+			continue
+		}
+		p.Conversations++
+
+		name := conv.GetName()
+		if name == "" {
+			name = conv.GetDisplayName()
+		}
+		if err := c.messages.SaveChat(clientDeviceID, chatJID.ToNonAD().String(), name, nil); err != nil {
+			c.log.Errorf("cannot store chat %v for client id: %v, %v", chatJID, clientDeviceID, err)
+		}
+
+		for _, historyMsg := range conv.GetMessages() {
+			msgEvt, err := cli.ParseWebMessage(chatJID, historyMsg.GetMessage())
+			if err != nil || (!cutoff.IsZero() && msgEvt.Info.Timestamp.Before(cutoff)) {
+				p.Skipped++
+				continue
+			}
+			m := NormalizeMessage(clientDeviceID, msgEvt.Info, msgEvt.Message)
+			if m == nil {
+				p.Skipped++
+				continue
+			}
+			if err := c.messages.Save(m); err != nil {
+				c.log.Errorf("cannot import message %v for client id: %v, %v", m.MessageID, clientDeviceID, err)
+				p.Skipped++
+				continue
+			}
+			if m.ID == 0 {
+				p.Duplicates++
+			} else {
+				p.Imported++
+			}
+		}
+	}
+
+	c.log.Infof("history sync %v for client id: %v at %v%%, imported %v messages", p.SyncType, clientDeviceID, p.Progress, p.Imported)
+	if err := c.historySyncs.Record(p); err != nil {
+		c.log.Errorf("cannot record history sync progress for client id: %v, %v", clientDeviceID, err)
+	}
+}
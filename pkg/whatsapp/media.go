@@ -0,0 +1,139 @@
+package whatsapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net/http"
+	"strings"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+// MessageText returns the human readable text of a message, which is either its body or its media caption.
+func MessageText(msg *waE2E.Message) string {
+	switch {
+	case msg.GetConversation() != "":
+		return msg.GetConversation()
+	case msg.GetExtendedTextMessage() != nil:
+		return msg.GetExtendedTextMessage().GetText()
+	case msg.GetImageMessage() != nil:
+		return msg.GetImageMessage().GetCaption()
+	case msg.GetVideoMessage() != nil:
+		return msg.GetVideoMessage().GetCaption()
+	case msg.GetDocumentMessage() != nil:
+		return msg.GetDocumentMessage().GetCaption()
+	}
+	return ""
+}
+
+// mediaTypeOf maps a mimetype to its whatsapp media type, anything that is not an image, video or audio is a document.
+func mediaTypeOf(mimetype string) whatsmeow.MediaType {
+	switch {
+	case strings.HasPrefix(mimetype, "image/"):
+		return whatsmeow.MediaImage
+	case strings.HasPrefix(mimetype, "video/"):
+		return whatsmeow.MediaVideo
+	case strings.HasPrefix(mimetype, "audio/"):
+		return whatsmeow.MediaAudio
+	}
+	return whatsmeow.MediaDocument
+}
+
+// BuildMediaMessage uploads the given file and wraps it into the message type matching its mimetype.
+// Anything that is not an image, video or audio is sent as a document.
+func BuildMediaMessage(ctx context.Context, cli *whatsmeow.Client, data []byte, mimetype string, filename string, caption string) (*waE2E.Message, error) {
+	if mimetype == "" {
+		mimetype = http.DetectContentType(data)
+	}
+	mediaType := mediaTypeOf(mimetype)
+	uploaded, err := cli.Upload(ctx, data, mediaType)
+	if err != nil {
+		return nil, err
+	}
+	return newMediaMessage(mediaType, mimetype, filename, caption, opusSeconds(data), uploaded), nil
+}
+
+// BuildNewsletterMediaMessage is BuildMediaMessage for newsletters, whose media is not encrypted.
+// The returned upload handle has to be sent along as whatsmeow.SendRequestExtra MediaHandle.
+func BuildNewsletterMediaMessage(ctx context.Context, cli *whatsmeow.Client, data []byte, mimetype string, filename string, caption string) (*waE2E.Message, string, error) {
+	if mimetype == "" {
+		mimetype = http.DetectContentType(data)
+	}
+	mediaType := mediaTypeOf(mimetype)
+	uploaded, err := cli.UploadNewsletter(ctx, data, mediaType)
+	if err != nil {
+		return nil, "", err
+	}
+	return newMediaMessage(mediaType, mimetype, filename, caption, opusSeconds(data), uploaded), uploaded.Handle, nil
+}
+
+// opusSeconds reads the duration of an ogg opus file, the format of whatsapp voice notes, from the granule
+// position of its last page. It returns nil for anything else, whatsapp then shows no duration.
+func opusSeconds(data []byte) *uint32 {
+	// the identification header: "OpusHead", version, channels, pre-skip
+	head := bytes.Index(data, []byte("OpusHead"))
+	last := bytes.LastIndex(data, []byte("OggS"))
+	if !bytes.HasPrefix(data, []byte("OggS")) || head < 0 || head+12 > len(data) || last+14 > len(data) {
+		return nil
+	}
+	preSkip := uint64(binary.LittleEndian.Uint16(data[head+10:]))
+	granule := binary.LittleEndian.Uint64(data[last+6:])
+	// opus granule positions always count 48kHz samples
+	if granule <= preSkip || granule == ^uint64(0) {
+		return nil
+	}
+	return proto.Uint32(uint32((granule - preSkip + 47_999) / 48_000))
+}
+
+func newMediaMessage(mediaType whatsmeow.MediaType, mimetype string, filename string, caption string, seconds *uint32, uploaded whatsmeow.UploadResponse) *waE2E.Message {
+	switch mediaType {
+	case whatsmeow.MediaImage:
+		return &waE2E.Message{ImageMessage: &waE2E.ImageMessage{
+			Caption:       proto.String(caption),
+			Mimetype:      proto.String(mimetype),
+			URL:           proto.String(uploaded.URL),
+			DirectPath:    proto.String(uploaded.DirectPath),
+			MediaKey:      uploaded.MediaKey,
+			FileEncSHA256: uploaded.FileEncSHA256,
+			FileSHA256:    uploaded.FileSHA256,
+			FileLength:    proto.Uint64(uploaded.FileLength),
+		}}
+	case whatsmeow.MediaVideo:
+		return &waE2E.Message{VideoMessage: &waE2E.VideoMessage{
+			Caption:       proto.String(caption),
+			Mimetype:      proto.String(mimetype),
+			URL:           proto.String(uploaded.URL),
+			DirectPath:    proto.String(uploaded.DirectPath),
+			MediaKey:      uploaded.MediaKey,
+			FileEncSHA256: uploaded.FileEncSHA256,
+			FileSHA256:    uploaded.FileSHA256,
+			FileLength:    proto.Uint64(uploaded.FileLength),
+		}}
+	case whatsmeow.MediaAudio:
+		return &waE2E.Message{AudioMessage: &waE2E.AudioMessage{
+			Seconds:       seconds,
+			Mimetype:      proto.String(mimetype),
+			URL:           proto.String(uploaded.URL),
+			DirectPath:    proto.String(uploaded.DirectPath),
+			MediaKey:      uploaded.MediaKey,
+			FileEncSHA256: uploaded.FileEncSHA256,
+			FileSHA256:    uploaded.FileSHA256,
+			FileLength:    proto.Uint64(uploaded.FileLength),
+		}}
+	}
+	return &waE2E.Message{DocumentMessage: &waE2E.DocumentMessage{
+		Caption:       proto.String(caption),
+		Mimetype:      proto.String(mimetype),
+		Title:         proto.String(filename),
+		FileName:      proto.String(filename),
+		URL:           proto.String(uploaded.URL),
+		DirectPath:    proto.String(uploaded.DirectPath),
+		MediaKey:      uploaded.MediaKey,
+		FileEncSHA256: uploaded.FileEncSHA256,
+		FileSHA256:    uploaded.FileSHA256,
+		FileLength:    proto.Uint64(uploaded.FileLength),
+	}}
+}
@@ -0,0 +1,223 @@
+package whatsapp
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/hrz8/whatsapp-api/pkg/blob"
+	"github.com/hrz8/whatsapp-api/pkg/fetch"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+)
+
+const mediaDownloadTimeout = 2 * time.Minute
+
+var (
+	ErrMediaNotFound = errors.New("media not found")
+	ErrMediaDisabled = errors.New("media storage is not configured")
+	ErrMediaNoURL    = errors.New("media storage does not support direct download urls")
+)
+
+// MediaDownloadConfig selects which incoming message types (e.g. MessageTypeImage) are downloaded automatically.
+// Media bigger than MaxSize bytes is skipped, a zero MaxSize means no limit.
+type MediaDownloadConfig struct {
+	Types   []string
+	MaxSize int64
+}
+
+func (cfg *MediaDownloadConfig) allows(m *Message) bool {
+	if cfg.MaxSize > 0 && m.MediaSize > cfg.MaxSize {
+		return false
+	}
+	for _, t := range cfg.Types {
+		if t == m.MediaType {
+			return true
+		}
+	}
+	return false
+}
+
+type Media struct {
+	ID          string    `json:"id"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type MediaRepo struct {
+	db *sql.DB
+}
+
+func (r *MediaRepo) Save(m *Media) error {
+	row := r.db.QueryRow(`INSERT INTO
+		whatsmeow_extended_media (
+			id,
+			content_type,
+			size
+		)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (id) DO UPDATE SET content_type = whatsmeow_extended_media.content_type
+		RETURNING created_at`,
+		m.ID,
+		m.ContentType,
+		m.Size,
+	)
+	return row.Scan(&m.CreatedAt)
+}
+
+func (r *MediaRepo) Get(id string) (*Media, error) {
+	row := r.db.QueryRow(`SELECT id, content_type, size, created_at FROM whatsmeow_extended_media WHERE id = $1`, id)
+	var i Media
+	err := row.Scan(
+		&i.ID,
+		&i.ContentType,
+		&i.Size,
+		&i.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrMediaNotFound
+	}
+	return &i, err
+}
+
+// StoreMedia puts the data into the blob store keyed by its SHA256, storing identical data only once.
+func (c *Client) StoreMedia(ctx context.Context, data []byte, contentType string) (*Media, error) {
+	if c.blobs == nil {
+		return nil, ErrMediaDisabled
+	}
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+
+	sum := sha256.Sum256(data)
+	media := &Media{
+		ID:          hex.EncodeToString(sum[:]),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+	}
+	exists, err := c.blobs.Exists(ctx, media.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		if err = c.blobs.Put(ctx, media.ID, data, contentType); err != nil {
+			return nil, err
+		}
+	}
+	return media, c.media.Save(media)
+}
+
+// OpenMedia returns the media metadata with its content, the caller has to close the reader.
+func (c *Client) OpenMedia(ctx context.Context, id string) (*Media, io.ReadCloser, error) {
+	if c.blobs == nil {
+		return nil, nil, ErrMediaDisabled
+	}
+	media, err := c.media.Get(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	rc, err := c.blobs.Get(ctx, id)
+	if errors.Is(err, blob.ErrNotFound) {
+		return nil, nil, ErrMediaNotFound
+	}
+	return media, rc, err
+}
+
+// MediaURL returns a presigned url downloading the media straight from the blob store, e.g. S3.
+func (c *Client) MediaURL(ctx context.Context, id string, expires time.Duration) (string, error) {
+	if c.blobs == nil {
+		return "", ErrMediaDisabled
+	}
+	presigner, ok := c.blobs.(blob.Presigner)
+	if !ok {
+		return "", ErrMediaNoURL
+	}
+	if _, err := c.media.Get(id); err != nil {
+		return "", err
+	}
+	return presigner.PresignGet(ctx, id, expires)
+}
+
+// UploadMedia keeps a copy of outgoing media in the blob store (when configured) before building its message,
+// the stored sent message is linked to it through the file SHA256.
+func (c *Client) UploadMedia(ctx context.Context, cli *whatsmeow.Client, data []byte, mimetype string, filename string, caption string) (*waE2E.Message, error) {
+	if c.blobs != nil {
+		if _, err := c.StoreMedia(ctx, data, mimetype); err != nil {
+			return nil, err
+		}
+	}
+	return BuildMediaMessage(ctx, cli, data, mimetype, filename, caption)
+}
+
+// UploadNewsletterMedia is UploadMedia for newsletters, the message has to be sent along with the returned extra.
+func (c *Client) UploadNewsletterMedia(ctx context.Context, cli *whatsmeow.Client, data []byte, mimetype string, filename string, caption string) (*waE2E.Message, whatsmeow.SendRequestExtra, error) {
+	if c.blobs != nil {
+		if _, err := c.StoreMedia(ctx, data, mimetype); err != nil {
+			return nil, whatsmeow.SendRequestExtra{}, err
+		}
+	}
+	msg, handle, err := BuildNewsletterMediaMessage(ctx, cli, data, mimetype, filename, caption)
+	return msg, whatsmeow.SendRequestExtra{MediaHandle: handle}, err
+}
+
+// FetchMedia downloads the file at url with the configured fetcher limits.
+func (c *Client) FetchMedia(ctx context.Context, url string) (*fetch.File, error) {
+	return c.fetcher.Fetch(ctx, url)
+}
+
+// UploadMediaURL fetches the media at url and uploads it like UploadMedia, the filename defaults to the one of the url.
+func (c *Client) UploadMediaURL(ctx context.Context, cli *whatsmeow.Client, url string, filename string, caption string) (*waE2E.Message, error) {
+	file, err := c.FetchMedia(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	if filename == "" {
+		filename = file.Filename
+	}
+	return c.UploadMedia(ctx, cli, file.Data, file.ContentType, filename, caption)
+}
+
+// linkSentMedia points a sent message to its stored media, outgoing media is stored before it is sent.
+func (c *Client) linkSentMedia(m *Message) {
+	if c.blobs == nil || m.MediaSHA256 == "" {
+		return
+	}
+	if _, err := c.media.Get(m.MediaSHA256); err == nil {
+		m.MediaID = m.MediaSHA256
+	}
+}
+
+// downloadMedia fetches and decrypts the media of a stored message in the background, references expire after a while.
+func (c *Client) downloadMedia(cli *whatsmeow.Client, clientDeviceID string, m *Message, msg *waE2E.Message) {
+	if c.blobs == nil || m.ID == 0 || m.MediaType == "" || !c.mediaDownload.allows(m) {
+		return
+	}
+
+	go func() {
+		c.mediaSem <- struct{}{}
+		defer func() { <-c.mediaSem }()
+
+		ctx, cancel := context.WithTimeout(context.Background(), mediaDownloadTimeout)
+		defer cancel()
+
+		data, err := cli.DownloadAny(msg)
+		if err != nil {
+			c.log.Warnf("cannot download media of message %v for client id: %v, %v", m.MessageID, clientDeviceID, err)
+			return
+		}
+		media, err := c.StoreMedia(ctx, data, m.MediaMimetype)
+		if err != nil {
+			c.log.Errorf("cannot store media of message %v for client id: %v, %v", m.MessageID, clientDeviceID, err)
+			return
+		}
+		if err = c.messages.SetMediaID(m.ID, media.ID); err != nil {
+			c.log.Errorf("cannot link media %v to message %v, %v", media.ID, m.MessageID, err)
+		}
+	}()
+}
@@ -0,0 +1,427 @@
+package whatsapp
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+const (
+	MessageTypeText     = "text"
+	MessageTypeImage    = "image"
+	MessageTypeVideo    = "video"
+	MessageTypeAudio    = "audio"
+	MessageTypeDocument = "document"
+	MessageTypeSticker  = "sticker"
+	MessageTypeLocation = "location"
+	MessageTypeContact  = "contact"
+	MessageTypeReaction = "reaction"
+	MessageTypePoll     = "poll"
+	MessageTypeOther    = "other"
+)
+
+// Message is the normalized form of a message we received or sent, as it is stored in postgres.
+type Message struct {
+	ID             int64     `json:"id"`
+	ClientDeviceID string    `json:"client_device_id"`
+	MessageID      string    `json:"message_id"`
+	ChatJID        string    `json:"chat_jid"`
+	SenderJID      string    `json:"sender_jid"`
+	FromMe         bool      `json:"from_me"`
+	PushName       string    `json:"push_name"`
+	Type           string    `json:"type"`
+	Text           string    `json:"text"`
+	MediaType      string    `json:"media_type,omitempty"`
+	MediaMimetype  string    `json:"media_mimetype,omitempty"`
+	MediaFilename  string    `json:"media_filename,omitempty"`
+	MediaSize      int64     `json:"media_size,omitempty"`
+	MediaSHA256    string    `json:"media_sha256,omitempty"`
+	MediaID        string    `json:"media_id,omitempty"`
+	QuotedID       string    `json:"quoted_id,omitempty"`
+	Timestamp      time.Time `json:"timestamp"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Chat carries the chat state synced with the phone, a muted chat without MutedUntil is muted forever.
+type Chat struct {
+	ClientDeviceID string     `json:"client_device_id"`
+	JID            string     `json:"jid"`
+	Name           string     `json:"name"`
+	LastMessageAt  *time.Time `json:"last_message_at"`
+	Archived       bool       `json:"archived"`
+	Pinned         bool       `json:"pinned"`
+	Muted          bool       `json:"muted"`
+	MutedUntil     *time.Time `json:"muted_until"`
+	Unread         bool       `json:"unread"`
+	ClearedAt      *time.Time `json:"cleared_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+type mediaFields interface {
+	GetMimetype() string
+	GetFileLength() uint64
+	GetFileSHA256() []byte
+}
+
+func (m *Message) setMedia(mediaType string, media mediaFields) {
+	m.Type = mediaType
+	m.MediaType = mediaType
+	m.MediaMimetype = media.GetMimetype()
+	m.MediaSize = int64(media.GetFileLength())
+	m.MediaSHA256 = hex.EncodeToString(media.GetFileSHA256())
+}
+
+// NormalizeMessage flattens a message into its stored form.
+// It returns nil for messages without user content, e.g. protocol or key distribution messages.
+func NormalizeMessage(clientDeviceID string, info types.MessageInfo, msg *waE2E.Message) *Message {
+	if msg == nil || msg.GetProtocolMessage() != nil {
+		return nil
+	}
+
+	m := &Message{
+		ClientDeviceID: clientDeviceID,
+		MessageID:      info.ID,
+		ChatJID:        info.Chat.ToNonAD().String(),
+		SenderJID:      info.Sender.ToNonAD().String(),
+		FromMe:         info.IsFromMe,
+		PushName:       info.PushName,
+		Type:           MessageTypeText,
+		Text:           MessageText(msg),
+		Timestamp:      info.Timestamp,
+	}
+
+	var ctxInfo *waE2E.ContextInfo
+	switch {
+	case msg.GetConversation() != "":
+	case msg.GetExtendedTextMessage() != nil:
+		ctxInfo = msg.GetExtendedTextMessage().GetContextInfo()
+	case msg.GetImageMessage() != nil:
+		m.setMedia(MessageTypeImage, msg.GetImageMessage())
+		ctxInfo = msg.GetImageMessage().GetContextInfo()
+	case msg.GetVideoMessage() != nil:
+		m.setMedia(MessageTypeVideo, msg.GetVideoMessage())
+		ctxInfo = msg.GetVideoMessage().GetContextInfo()
+	case msg.GetAudioMessage() != nil:
+		m.setMedia(MessageTypeAudio, msg.GetAudioMessage())
+		ctxInfo = msg.GetAudioMessage().GetContextInfo()
+	case msg.GetDocumentMessage() != nil:
+		m.setMedia(MessageTypeDocument, msg.GetDocumentMessage())
+		m.MediaFilename = msg.GetDocumentMessage().GetFileName()
+		ctxInfo = msg.GetDocumentMessage().GetContextInfo()
+	case msg.GetStickerMessage() != nil:
+		m.setMedia(MessageTypeSticker, msg.GetStickerMessage())
+		ctxInfo = msg.GetStickerMessage().GetContextInfo()
+	case msg.GetLocationMessage() != nil:
+		m.Type = MessageTypeLocation
+		m.Text = msg.GetLocationMessage().GetName()
+		ctxInfo = msg.GetLocationMessage().GetContextInfo()
+	case msg.GetContactMessage() != nil:
+		m.Type = MessageTypeContact
+		m.Text = msg.GetContactMessage().GetDisplayName()
+		ctxInfo = msg.GetContactMessage().GetContextInfo()
+	case msg.GetReactionMessage() != nil:
+		m.Type = MessageTypeReaction
+		m.Text = msg.GetReactionMessage().GetText()
+		m.QuotedID = msg.GetReactionMessage().GetKey().GetID()
+	case msg.GetPollCreationMessage() != nil:
+		m.Type = MessageTypePoll
+		m.Text = msg.GetPollCreationMessage().GetName()
+	case msg.GetPollCreationMessageV3() != nil:
+		m.Type = MessageTypePoll
+		m.Text = msg.GetPollCreationMessageV3().GetName()
+	case msg.GetSenderKeyDistributionMessage() != nil:
+		return nil
+	default:
+		m.Type = MessageTypeOther
+	}
+	if ctxInfo != nil {
+		m.QuotedID = ctxInfo.GetStanzaID()
+	}
+	return m
+}
+
+type MessageRepo struct {
+	db *sql.DB
+}
+
+// SaveChat creates the chat or updates it, an empty name or a nil last message time keeps the current value.
+func (r *MessageRepo) SaveChat(clientDeviceID string, jid string, name string, lastMessageAt *time.Time) error {
+	_, err := r.db.Exec(`INSERT INTO
+		whatsmeow_extended_chat (
+			client_device_id,
+			jid,
+			name,
+			last_message_at
+		)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (client_device_id, jid) DO UPDATE SET
+			name = CASE WHEN EXCLUDED.name = '' THEN whatsmeow_extended_chat.name ELSE EXCLUDED.name END,
+			last_message_at = GREATEST(whatsmeow_extended_chat.last_message_at, EXCLUDED.last_message_at),
+			updated_at = now()`,
+		clientDeviceID,
+		jid,
+		name,
+		lastMessageAt,
+	)
+	return err
+}
+
+// Save stores the message once, duplicates (same device, chat and message id) are ignored and keep a zero ID.
+// The chat is created or bumped along with it.
+func (r *MessageRepo) Save(m *Message) error {
+	chatName := ""
+	if !m.FromMe && m.ChatJID == m.SenderJID {
+		chatName = m.PushName
+	}
+	err := r.SaveChat(m.ClientDeviceID, m.ChatJID, chatName, &m.Timestamp)
+	if err != nil {
+		return err
+	}
+
+	row := r.db.QueryRow(`INSERT INTO
+		whatsmeow_extended_message (
+			client_device_id,
+			message_id,
+			chat_jid,
+			sender_jid,
+			from_me,
+			push_name,
+			type,
+			text,
+			media_type,
+			media_mimetype,
+			media_filename,
+			media_size,
+			media_sha256,
+			media_id,
+			quoted_id,
+			timestamp
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		ON CONFLICT (client_device_id, chat_jid, message_id) DO NOTHING
+		RETURNING id, created_at`,
+		m.ClientDeviceID,
+		m.MessageID,
+		m.ChatJID,
+		m.SenderJID,
+		m.FromMe,
+		m.PushName,
+		m.Type,
+		m.Text,
+		m.MediaType,
+		m.MediaMimetype,
+		m.MediaFilename,
+		m.MediaSize,
+		m.MediaSHA256,
+		m.MediaID,
+		m.QuotedID,
+		m.Timestamp,
+	)
+	err = row.Scan(&m.ID, &m.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	return err
+}
+
+// ListChats returns the chats pinned first, then the most recent. Deleted chats are left out until a new
+// message arrives, archived filters by archive state when given.
+func (r *MessageRepo) ListChats(clientDeviceID string, archived *bool, limit int, offset int) ([]*Chat, error) {
+	rows, err := r.db.Query(`SELECT client_device_id, jid, name, last_message_at, archived, pinned, muted, muted_until,
+			unread, cleared_at, created_at, updated_at
+		FROM whatsmeow_extended_chat
+		WHERE client_device_id = $1
+			AND (deleted_at IS NULL OR last_message_at > deleted_at)
+			AND ($2::BOOLEAN IS NULL OR archived = $2)
+		ORDER BY pinned DESC, last_message_at DESC NULLS LAST, jid
+		LIMIT $3 OFFSET $4`,
+		clientDeviceID,
+		archived,
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	chats := make([]*Chat, 0)
+	for rows.Next() {
+		var i Chat
+		err := rows.Scan(
+			&i.ClientDeviceID,
+			&i.JID,
+			&i.Name,
+			&i.LastMessageAt,
+			&i.Archived,
+			&i.Pinned,
+			&i.Muted,
+			&i.MutedUntil,
+			&i.Unread,
+			&i.ClearedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if i.Muted && i.MutedUntil != nil && i.MutedUntil.Before(now) {
+			i.Muted, i.MutedUntil = false, nil
+		}
+		chats = append(chats, &i)
+	}
+	return chats, rows.Err()
+}
+
+const messageColumns = `id, client_device_id, message_id, chat_jid, sender_jid, from_me, push_name, type, text,
+	media_type, media_mimetype, media_filename, media_size, media_sha256, media_id, quoted_id, timestamp, created_at`
+
+// scanMessage reads the messageColumns, followed by any extra selected column.
+func scanMessage(row interface{ Scan(...any) error }, extra ...any) (*Message, error) {
+	var i Message
+	dest := []any{
+		&i.ID,
+		&i.ClientDeviceID,
+		&i.MessageID,
+		&i.ChatJID,
+		&i.SenderJID,
+		&i.FromMe,
+		&i.PushName,
+		&i.Type,
+		&i.Text,
+		&i.MediaType,
+		&i.MediaMimetype,
+		&i.MediaFilename,
+		&i.MediaSize,
+		&i.MediaSHA256,
+		&i.MediaID,
+		&i.QuotedID,
+		&i.Timestamp,
+		&i.CreatedAt,
+	}
+	err := row.Scan(append(dest, extra...)...)
+	return &i, err
+}
+
+func (r *MessageRepo) SetMediaID(id int64, mediaID string) error {
+	_, err := r.db.Exec("UPDATE whatsmeow_extended_message SET media_id = $2 WHERE id = $1", id, mediaID)
+	return err
+}
+
+// ListMessages returns the messages of a chat, newest first.
+func (r *MessageRepo) ListMessages(clientDeviceID string, chatJID string, limit int, offset int) ([]*Message, error) {
+	rows, err := r.db.Query(`SELECT `+messageColumns+`
+		FROM whatsmeow_extended_message
+		WHERE client_device_id = $1 AND chat_jid = $2
+		ORDER BY timestamp DESC, id DESC
+		LIMIT $3 OFFSET $4`,
+		clientDeviceID,
+		chatJID,
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := make([]*Message, 0)
+	for rows.Next() {
+		m, err := scanMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
+
+// LastMessage returns the newest stored message of the chat, nil when there is none.
+func (r *MessageRepo) LastMessage(clientDeviceID string, chatJID string) (*Message, error) {
+	row := r.db.QueryRow(`SELECT `+messageColumns+`
+		FROM whatsmeow_extended_message
+		WHERE client_device_id = $1 AND chat_jid = $2
+		ORDER BY timestamp DESC, id DESC
+		LIMIT 1`,
+		clientDeviceID,
+		chatJID,
+	)
+	m, err := scanMessage(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return m, err
+}
+
+// GetMessages returns the stored messages of a chat by message id, unknown ids are left out.
+func (r *MessageRepo) GetMessages(clientDeviceID string, chatJID string, messageIDs []string) (map[string]*Message, error) {
+	rows, err := r.db.Query(`SELECT `+messageColumns+`
+		FROM whatsmeow_extended_message
+		WHERE client_device_id = $1 AND chat_jid = $2 AND message_id = ANY($3)`,
+		clientDeviceID,
+		chatJID,
+		messageIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := make(map[string]*Message)
+	for rows.Next() {
+		m, err := scanMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages[m.MessageID] = m
+	}
+	return messages, rows.Err()
+}
+
+func (c *Client) storeMessage(cli *whatsmeow.Client, clientDeviceID string, evt *events.Message) {
+	m := NormalizeMessage(clientDeviceID, evt.Info, evt.Message)
+	if m == nil {
+		return
+	}
+	if err := c.messages.Save(m); err != nil {
+		c.log.Errorf("cannot store message %v for client id: %v, %v", evt.Info.ID, clientDeviceID, err)
+		return
+	}
+	// duplicates keep a zero ID and were published already
+	if !m.FromMe && m.ID != 0 {
+		c.publish(clientDeviceID, EventMessageReceived, m)
+	}
+	c.downloadMedia(cli, clientDeviceID, m, evt.Message)
+}
+
+func (c *Client) storeSentMessage(cli *whatsmeow.Client, clientDeviceID string, to types.JID, msg *waE2E.Message, resp whatsmeow.SendResponse) {
+	info := types.MessageInfo{
+		MessageSource: types.MessageSource{
+			Chat:     to,
+			IsFromMe: true,
+			IsGroup:  to.Server == types.GroupServer,
+		},
+		ID:        resp.ID,
+		PushName:  cli.Store.PushName,
+		Timestamp: resp.Timestamp,
+	}
+	if cli.Store.ID != nil {
+		info.Sender = cli.Store.ID.ToNonAD()
+	}
+	m := NormalizeMessage(clientDeviceID, info, msg)
+	if m == nil {
+		return
+	}
+	c.linkSentMedia(m)
+	if err := c.messages.Save(m); err != nil {
+		c.log.Errorf("cannot store sent message %v for client id: %v, %v", resp.ID, clientDeviceID, err)
+	}
+}
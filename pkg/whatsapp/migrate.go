@@ -8,7 +8,7 @@ import (
 
 type upgradeFunc func(*sql.Tx) error
 
-var Upgrades = [1]upgradeFunc{version1}
+var Upgrades = [13]upgradeFunc{version1, version2, version3, version4, version5, version6, version7, version8, version9, version10, version11, version12, version13}
 
 type Migration struct {
 	db  *sql.DB
@@ -87,3 +87,215 @@ func version1(tx *sql.Tx) (err error) {
 
 	return
 }
+
+func version2(tx *sql.Tx) (err error) {
+	_, err = tx.Exec(`CREATE TABLE IF NOT EXISTS "whatsmeow_extended_template" (
+		"id" SERIAL NOT NULL,
+		"name" VARCHAR(100) NOT NULL,
+		"language" VARCHAR(10) NOT NULL,
+		"body" TEXT NOT NULL,
+		"variables" JSONB NOT NULL DEFAULT '[]',
+		"created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+		"updated_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+		CONSTRAINT "templates_pkey" PRIMARY KEY ("id"),
+		CONSTRAINT "templates_name_language_key" UNIQUE ("name", "language")
+	);`)
+
+	return
+}
+
+func version3(tx *sql.Tx) (err error) {
+	_, err = tx.Exec(`CREATE TABLE IF NOT EXISTS "whatsmeow_extended_suppression" (
+		"id" SERIAL NOT NULL,
+		"client_device_id" VARCHAR(50) NOT NULL DEFAULT '',
+		"jid" VARCHAR(50) NOT NULL,
+		"reason" TEXT NOT NULL DEFAULT '',
+		"created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+		CONSTRAINT "suppressions_pkey" PRIMARY KEY ("id"),
+		CONSTRAINT "suppressions_client_device_id_jid_key" UNIQUE ("client_device_id", "jid")
+	);`)
+
+	return
+}
+
+func version4(tx *sql.Tx) (err error) {
+	_, err = tx.Exec(`CREATE TABLE IF NOT EXISTS "whatsmeow_extended_autoreply_rule" (
+		"id" SERIAL NOT NULL,
+		"client_device_id" VARCHAR(50) NOT NULL,
+		"name" VARCHAR(100) NOT NULL,
+		"priority" INTEGER NOT NULL DEFAULT 0,
+		"enabled" BOOLEAN NOT NULL DEFAULT true,
+		"match" JSONB NOT NULL DEFAULT '{}',
+		"action" JSONB NOT NULL DEFAULT '{}',
+		"cooldown_seconds" INTEGER NOT NULL DEFAULT 0,
+		"created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+		"updated_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+		CONSTRAINT "autoreply_rules_pkey" PRIMARY KEY ("id")
+	);
+
+	CREATE INDEX IF NOT EXISTS "autoreply_rules_client_device_id_idx" ON "whatsmeow_extended_autoreply_rule" ("client_device_id");`)
+
+	return
+}
+
+func version5(tx *sql.Tx) (err error) {
+	_, err = tx.Exec(`CREATE TABLE IF NOT EXISTS "whatsmeow_extended_schedule" (
+		"client_device_id" VARCHAR(50) NOT NULL,
+		"enabled" BOOLEAN NOT NULL DEFAULT true,
+		"timezone" VARCHAR(64) NOT NULL DEFAULT 'UTC',
+		"weekly" JSONB NOT NULL DEFAULT '{}',
+		"holidays" JSONB NOT NULL DEFAULT '[]',
+		"away_message" TEXT NOT NULL DEFAULT '',
+		"away_cooldown_seconds" INTEGER NOT NULL DEFAULT 0,
+		"updated_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+		CONSTRAINT "schedules_pkey" PRIMARY KEY ("client_device_id")
+	);`)
+
+	return
+}
+
+func version6(tx *sql.Tx) (err error) {
+	_, err = tx.Exec(`CREATE TABLE IF NOT EXISTS "whatsmeow_extended_chat" (
+		"client_device_id" VARCHAR(50) NOT NULL,
+		"jid" VARCHAR(100) NOT NULL,
+		"name" TEXT NOT NULL DEFAULT '',
+		"last_message_at" TIMESTAMPTZ,
+		"created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+		"updated_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+		CONSTRAINT "chats_pkey" PRIMARY KEY ("client_device_id", "jid")
+	);
+
+	CREATE TABLE IF NOT EXISTS "whatsmeow_extended_message" (
+		"id" BIGSERIAL NOT NULL,
+		"client_device_id" VARCHAR(50) NOT NULL,
+		"message_id" VARCHAR(100) NOT NULL,
+		"chat_jid" VARCHAR(100) NOT NULL,
+		"sender_jid" VARCHAR(100) NOT NULL,
+		"from_me" BOOLEAN NOT NULL DEFAULT false,
+		"push_name" TEXT NOT NULL DEFAULT '',
+		"type" VARCHAR(30) NOT NULL,
+		"text" TEXT NOT NULL DEFAULT '',
+		"media_type" VARCHAR(30) NOT NULL DEFAULT '',
+		"media_mimetype" VARCHAR(255) NOT NULL DEFAULT '',
+		"media_filename" TEXT NOT NULL DEFAULT '',
+		"media_size" BIGINT NOT NULL DEFAULT 0,
+		"media_sha256" VARCHAR(64) NOT NULL DEFAULT '',
+		"quoted_id" VARCHAR(100) NOT NULL DEFAULT '',
+		"timestamp" TIMESTAMPTZ NOT NULL,
+		"created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+		CONSTRAINT "messages_pkey" PRIMARY KEY ("id"),
+		CONSTRAINT "messages_client_device_id_chat_jid_message_id_key" UNIQUE ("client_device_id", "chat_jid", "message_id")
+	);
+
+	CREATE INDEX IF NOT EXISTS "messages_chat_timestamp_idx" ON "whatsmeow_extended_message" ("client_device_id", "chat_jid", "timestamp" DESC);`)
+
+	return
+}
+
+func version7(tx *sql.Tx) (err error) {
+	_, err = tx.Exec(`CREATE TABLE IF NOT EXISTS "whatsmeow_extended_history_sync" (
+		"client_device_id" VARCHAR(50) NOT NULL,
+		"sync_type" VARCHAR(30) NOT NULL DEFAULT '',
+		"progress" INTEGER NOT NULL DEFAULT 0,
+		"chunks" INTEGER NOT NULL DEFAULT 0,
+		"conversations" INTEGER NOT NULL DEFAULT 0,
+		"imported" INTEGER NOT NULL DEFAULT 0,
+		"duplicates" INTEGER NOT NULL DEFAULT 0,
+		"skipped" INTEGER NOT NULL DEFAULT 0,
+		"started_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+		"updated_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+		CONSTRAINT "history_syncs_pkey" PRIMARY KEY ("client_device_id")
+	);`)
+
+	return
+}
+
+func version8(tx *sql.Tx) (err error) {
+	_, err = tx.Exec(`ALTER TABLE "whatsmeow_extended_message" ADD COLUMN IF NOT EXISTS "text_search" TSVECTOR
+		GENERATED ALWAYS AS (to_tsvector('simple', "text")) STORED;
+
+	CREATE INDEX IF NOT EXISTS "messages_text_search_idx" ON "whatsmeow_extended_message" USING GIN ("text_search");`)
+
+	return
+}
+
+func version9(tx *sql.Tx) (err error) {
+	_, err = tx.Exec(`CREATE TABLE IF NOT EXISTS "whatsmeow_extended_media" (
+		"id" VARCHAR(64) NOT NULL,
+		"content_type" VARCHAR(255) NOT NULL,
+		"size" BIGINT NOT NULL,
+		"created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+		CONSTRAINT "media_pkey" PRIMARY KEY ("id")
+	);
+
+	ALTER TABLE "whatsmeow_extended_message" ADD COLUMN IF NOT EXISTS "media_id" VARCHAR(64) NOT NULL DEFAULT '';`)
+
+	return
+}
+
+func version10(tx *sql.Tx) (err error) {
+	_, err = tx.Exec(`CREATE TABLE IF NOT EXISTS "whatsmeow_extended_contact_check" (
+		"number" VARCHAR(20) NOT NULL,
+		"is_in" BOOLEAN NOT NULL,
+		"jid" VARCHAR(100) NOT NULL DEFAULT '',
+		"is_business" BOOLEAN NOT NULL DEFAULT false,
+		"business_name" TEXT NOT NULL DEFAULT '',
+		"checked_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+		CONSTRAINT "contact_checks_pkey" PRIMARY KEY ("number")
+	);`)
+
+	return
+}
+
+func version11(tx *sql.Tx) (err error) {
+	_, err = tx.Exec(`CREATE TABLE IF NOT EXISTS "whatsmeow_extended_device_settings" (
+		"client_device_id" VARCHAR(50) NOT NULL,
+		"default_country_code" VARCHAR(3) NOT NULL DEFAULT '',
+		"updated_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+		CONSTRAINT "device_settings_pkey" PRIMARY KEY ("client_device_id")
+	);`)
+
+	return
+}
+
+func version12(tx *sql.Tx) (err error) {
+	_, err = tx.Exec(`ALTER TABLE "whatsmeow_extended_device_settings"
+		ADD COLUMN IF NOT EXISTS "auto_mark_read" BOOLEAN NOT NULL DEFAULT false;
+
+	CREATE TABLE IF NOT EXISTS "whatsmeow_extended_receipt" (
+		"client_device_id" VARCHAR(50) NOT NULL,
+		"chat_jid" VARCHAR(100) NOT NULL,
+		"message_id" VARCHAR(100) NOT NULL,
+		"recipient_jid" VARCHAR(100) NOT NULL,
+		"delivered_at" TIMESTAMPTZ,
+		"read_at" TIMESTAMPTZ,
+		"played_at" TIMESTAMPTZ,
+
+		CONSTRAINT "receipts_pkey" PRIMARY KEY ("client_device_id", "chat_jid", "message_id", "recipient_jid")
+	);`)
+
+	return
+}
+
+func version13(tx *sql.Tx) (err error) {
+	_, err = tx.Exec(`ALTER TABLE "whatsmeow_extended_chat"
+		ADD COLUMN IF NOT EXISTS "archived" BOOLEAN NOT NULL DEFAULT false,
+		ADD COLUMN IF NOT EXISTS "pinned" BOOLEAN NOT NULL DEFAULT false,
+		ADD COLUMN IF NOT EXISTS "muted" BOOLEAN NOT NULL DEFAULT false,
+		ADD COLUMN IF NOT EXISTS "muted_until" TIMESTAMPTZ,
+		ADD COLUMN IF NOT EXISTS "unread" BOOLEAN NOT NULL DEFAULT false,
+		ADD COLUMN IF NOT EXISTS "cleared_at" TIMESTAMPTZ,
+		ADD COLUMN IF NOT EXISTS "deleted_at" TIMESTAMPTZ;`)
+
+	return
+}
@@ -0,0 +1,272 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// simulated typing takes TypingPerChar for every character of the message, between MinTyping and MaxTyping
+const (
+	TypingPerChar = 50 * time.Millisecond
+	MinTyping     = time.Second
+	MaxTyping     = 10 * time.Second
+)
+
+const (
+	ChatComposing = "composing"
+	ChatRecording = "recording"
+	ChatPaused    = "paused"
+)
+
+const EventPresence = "contact.presence"
+
+var (
+	ErrInvalidPresence     = errors.New("presence must be either available or unavailable")
+	ErrInvalidChatPresence = errors.New("chat presence must be one of composing, recording or paused")
+	ErrNotSubscribed       = errors.New("presence of the contact is not subscribed")
+)
+
+func ParsePresence(presence string) (types.Presence, error) {
+	switch p := types.Presence(presence); p {
+	case types.PresenceAvailable, types.PresenceUnavailable:
+		return p, nil
+	}
+	return "", ErrInvalidPresence
+}
+
+// ParseChatPresence maps the chat state to whatsapp's, recording is composing an audio message.
+func ParseChatPresence(state string) (types.ChatPresence, types.ChatPresenceMedia, error) {
+	switch state {
+	case ChatComposing:
+		return types.ChatPresenceComposing, types.ChatPresenceMediaText, nil
+	case ChatRecording:
+		return types.ChatPresenceComposing, types.ChatPresenceMediaAudio, nil
+	case ChatPaused:
+		return types.ChatPresencePaused, types.ChatPresenceMediaText, nil
+	}
+	return "", "", ErrInvalidChatPresence
+}
+
+// TypingDuration is how long a person would take to type the text.
+func TypingDuration(text string) time.Duration {
+	return min(max(time.Duration(len([]rune(text)))*TypingPerChar, MinTyping), MaxTyping)
+}
+
+// SimulateTyping shows the device composing the message in the chat for its TypingDuration, or recording
+// audio messages for as long as they last, and pauses again. Audio without a known duration records for
+// MinTyping, see opusSeconds. It blocks until then or until ctx is done.
+func SimulateTyping(ctx context.Context, cli *whatsmeow.Client, to types.JID, msg *waE2E.Message) error {
+	media := types.ChatPresenceMediaText
+	duration := TypingDuration(MessageText(msg))
+	if audio := msg.GetAudioMessage(); audio != nil {
+		media = types.ChatPresenceMediaAudio
+		duration = min(max(time.Duration(audio.GetSeconds())*time.Second, MinTyping), MaxTyping)
+	}
+	if err := cli.SendChatPresence(to, types.ChatPresenceComposing, media); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+	if err := cli.SendChatPresence(to, types.ChatPresencePaused, types.ChatPresenceMediaText); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// ContactPresence is the latest known presence of a subscribed contact. LastSeen is nil while nothing was
+// received yet or when the contact hides it, UpdatedAt is nil until the first update arrives.
+type ContactPresence struct {
+	JID       string     `json:"jid"`
+	Online    bool       `json:"online"`
+	LastSeen  *time.Time `json:"last_seen"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+// presenceTracker keeps the subscribed contacts of every device in memory, whatsapp forgets the subscriptions
+// on every reconnect so they are sent again then. It also remembers which devices this service marked available,
+// whatsapp forgets that on reconnect too.
+type presenceTracker struct {
+	mut       sync.Mutex
+	devices   map[string]map[types.JID]*ContactPresence
+	available map[string]bool
+}
+
+func newPresenceTracker() *presenceTracker {
+	return &presenceTracker{
+		devices:   make(map[string]map[types.JID]*ContactPresence),
+		available: make(map[string]bool),
+	}
+}
+
+func (t *presenceTracker) isAvailable(clientDeviceID string) bool {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+	return t.available[clientDeviceID]
+}
+
+func (t *presenceTracker) setAvailable(clientDeviceID string, available bool) {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+	if available {
+		t.available[clientDeviceID] = true
+	} else {
+		delete(t.available, clientDeviceID)
+	}
+}
+
+func (t *presenceTracker) add(clientDeviceID string, jid types.JID) *ContactPresence {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+	contacts, ok := t.devices[clientDeviceID]
+	if !ok {
+		contacts = make(map[types.JID]*ContactPresence)
+		t.devices[clientDeviceID] = contacts
+	}
+	presence, ok := contacts[jid]
+	if !ok {
+		presence = &ContactPresence{JID: jid.String()}
+		contacts[jid] = presence
+	}
+	result := *presence
+	return &result
+}
+
+func (t *presenceTracker) remove(clientDeviceID string, jid types.JID) bool {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+	if _, ok := t.devices[clientDeviceID][jid]; !ok {
+		return false
+	}
+	delete(t.devices[clientDeviceID], jid)
+	return true
+}
+
+func (t *presenceTracker) get(clientDeviceID string, jid types.JID) *ContactPresence {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+	presence, ok := t.devices[clientDeviceID][jid]
+	if !ok {
+		return nil
+	}
+	result := *presence
+	return &result
+}
+
+func (t *presenceTracker) all(clientDeviceID string) []*ContactPresence {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+	result := make([]*ContactPresence, 0, len(t.devices[clientDeviceID]))
+	for _, presence := range t.devices[clientDeviceID] {
+		p := *presence
+		result = append(result, &p)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].JID < result[j].JID })
+	return result
+}
+
+func (t *presenceTracker) jids(clientDeviceID string) []types.JID {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+	jids := make([]types.JID, 0, len(t.devices[clientDeviceID]))
+	for jid := range t.devices[clientDeviceID] {
+		jids = append(jids, jid)
+	}
+	return jids
+}
+
+// update stores the event of a subscribed contact, it reports false for contacts nobody subscribed to.
+func (t *presenceTracker) update(clientDeviceID string, evt *events.Presence) (*ContactPresence, bool) {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+	presence, ok := t.devices[clientDeviceID][evt.From.ToNonAD()]
+	if !ok {
+		return nil, false
+	}
+	now := time.Now().UTC()
+	presence.Online = !evt.Unavailable
+	presence.UpdatedAt = &now
+	if !evt.LastSeen.IsZero() {
+		lastSeen := evt.LastSeen.UTC()
+		presence.LastSeen = &lastSeen
+	} else if !evt.Unavailable {
+		presence.LastSeen = &now
+	}
+	result := *presence
+	return &result, true
+}
+
+// SetPresence marks the device online or offline for its contacts, which needs a push name.
+func (c *Client) SetPresence(cli *whatsmeow.Client, clientDeviceID string, presence types.Presence) error {
+	if err := cli.SendPresence(presence); err != nil {
+		return err
+	}
+	c.presences.setAvailable(clientDeviceID, presence == types.PresenceAvailable)
+	return nil
+}
+
+// SubscribePresence starts tracking the presence of the contact. Whatsapp only shares presence with online
+// devices, so a device that is not available yet is marked available first, which needs a push name.
+// It then stays available, and shows as online to its contacts, until SetPresence marks it unavailable.
+func (c *Client) SubscribePresence(cli *whatsmeow.Client, clientDeviceID string, jid types.JID) (*ContactPresence, error) {
+	if !c.presences.isAvailable(clientDeviceID) {
+		if err := c.SetPresence(cli, clientDeviceID, types.PresenceAvailable); err != nil {
+			return nil, err
+		}
+	}
+	if err := cli.SubscribePresence(jid); err != nil {
+		return nil, err
+	}
+	return c.presences.add(clientDeviceID, jid), nil
+}
+
+// UnsubscribePresence stops tracking the contact. Whatsapp has no way to unsubscribe, its updates are
+// ignored until the next reconnect ends the subscription.
+func (c *Client) UnsubscribePresence(clientDeviceID string, jid types.JID) error {
+	if !c.presences.remove(clientDeviceID, jid) {
+		return ErrNotSubscribed
+	}
+	return nil
+}
+
+func (c *Client) Presence(clientDeviceID string, jid types.JID) (*ContactPresence, error) {
+	presence := c.presences.get(clientDeviceID, jid)
+	if presence == nil {
+		return nil, ErrNotSubscribed
+	}
+	return presence, nil
+}
+
+func (c *Client) Presences(clientDeviceID string) []*ContactPresence {
+	return c.presences.all(clientDeviceID)
+}
+
+func (c *Client) handlePresence(clientDeviceID string, evt *events.Presence) {
+	presence, ok := c.presences.update(clientDeviceID, evt)
+	if !ok {
+		return
+	}
+	c.publish(clientDeviceID, EventPresence, presence)
+}
+
+// resubscribePresence renews the subscriptions after a reconnect. The device is not marked available again,
+// so updates only arrive once SetPresence or a new subscription does.
+func (c *Client) resubscribePresence(cli *whatsmeow.Client, clientDeviceID string) {
+	for _, jid := range c.presences.jids(clientDeviceID) {
+		if err := cli.SubscribePresence(jid); err != nil {
+			c.log.Warnf("cannot resubscribe presence of %v for client id: %v, %v", jid, clientDeviceID, err)
+		}
+	}
+}
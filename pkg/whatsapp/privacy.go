@@ -0,0 +1,142 @@
+package whatsapp
+
+import (
+	"errors"
+	"fmt"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+var ErrInvalidPrivacySetting = errors.New("privacy setting value is not allowed")
+
+// PrivacySettingError tells which setting got which value, Allowed lists what it accepts.
+type PrivacySettingError struct {
+	Name    string
+	Value   types.PrivacySetting
+	Allowed []types.PrivacySetting
+}
+
+func (e *PrivacySettingError) Error() string {
+	return fmt.Sprintf("privacy setting %s cannot be %q", e.Name, e.Value)
+}
+
+func (e *PrivacySettingError) Unwrap() error {
+	return ErrInvalidPrivacySetting
+}
+
+var (
+	privacyAudience = []types.PrivacySetting{
+		types.PrivacySettingAll, types.PrivacySettingContacts, types.PrivacySettingContactBlacklist, types.PrivacySettingNone,
+	}
+	privacyValues = map[types.PrivacySettingType][]types.PrivacySetting{
+		types.PrivacySettingTypeGroupAdd:     privacyAudience,
+		types.PrivacySettingTypeLastSeen:     privacyAudience,
+		types.PrivacySettingTypeStatus:       privacyAudience,
+		types.PrivacySettingTypeProfile:      privacyAudience,
+		types.PrivacySettingTypeReadReceipts: {types.PrivacySettingAll, types.PrivacySettingNone},
+		types.PrivacySettingTypeOnline:       {types.PrivacySettingAll, types.PrivacySettingMatchLastSeen},
+		types.PrivacySettingTypeCallAdd:      {types.PrivacySettingAll, types.PrivacySettingKnown},
+	}
+)
+
+type PrivacySettings struct {
+	GroupAdd     types.PrivacySetting `json:"group_add"`
+	LastSeen     types.PrivacySetting `json:"last_seen"`
+	Status       types.PrivacySetting `json:"status"`
+	Profile      types.PrivacySetting `json:"profile"`
+	ReadReceipts types.PrivacySetting `json:"read_receipts"`
+	Online       types.PrivacySetting `json:"online"`
+	CallAdd      types.PrivacySetting `json:"call_add"`
+}
+
+func NewPrivacySettings(s *types.PrivacySettings) *PrivacySettings {
+	return &PrivacySettings{
+		GroupAdd:     s.GroupAdd,
+		LastSeen:     s.LastSeen,
+		Status:       s.Status,
+		Profile:      s.Profile,
+		ReadReceipts: s.ReadReceipts,
+		Online:       s.Online,
+		CallAdd:      s.CallAdd,
+	}
+}
+
+// PrivacyChange is a single setting to change, Name is the json name of the PrivacySettings field.
+type PrivacyChange struct {
+	Name  string
+	Type  types.PrivacySettingType
+	Value types.PrivacySetting
+}
+
+// ValidatePrivacyChanges checks every change before any is applied, so a bad value changes nothing.
+func ValidatePrivacyChanges(changes []PrivacyChange) error {
+	for _, change := range changes {
+		allowed := privacyValues[change.Type]
+		valid := false
+		for _, value := range allowed {
+			valid = valid || value == change.Value
+		}
+		if !valid {
+			return &PrivacySettingError{Name: change.Name, Value: change.Value, Allowed: allowed}
+		}
+	}
+	return nil
+}
+
+// SetPrivacySettings applies the changes one by one and returns the resulting settings.
+func SetPrivacySettings(cli *whatsmeow.Client, changes []PrivacyChange) (*PrivacySettings, error) {
+	if err := ValidatePrivacyChanges(changes); err != nil {
+		return nil, err
+	}
+	settings, err := cli.TryFetchPrivacySettings(false)
+	if err != nil {
+		return nil, err
+	}
+	for _, change := range changes {
+		updated, err := cli.SetPrivacySetting(change.Type, change.Value)
+		if err != nil {
+			return nil, err
+		}
+		settings = &updated
+	}
+	return NewPrivacySettings(settings), nil
+}
+
+func GetPrivacySettings(cli *whatsmeow.Client) (*PrivacySettings, error) {
+	settings, err := cli.TryFetchPrivacySettings(true)
+	if err != nil {
+		return nil, err
+	}
+	return NewPrivacySettings(settings), nil
+}
+
+func Blocklist(cli *whatsmeow.Client) ([]string, error) {
+	blocklist, err := cli.GetBlocklist()
+	if err != nil {
+		return nil, err
+	}
+	return blockedJIDs(blocklist), nil
+}
+
+// Block blocks or unblocks the user and returns the updated blocklist.
+func Block(cli *whatsmeow.Client, jid types.JID, block bool) ([]string, error) {
+	action := events.BlocklistChangeActionBlock
+	if !block {
+		action = events.BlocklistChangeActionUnblock
+	}
+	blocklist, err := cli.UpdateBlocklist(jid, action)
+	if err != nil {
+		return nil, err
+	}
+	return blockedJIDs(blocklist), nil
+}
+
+func blockedJIDs(blocklist *types.Blocklist) []string {
+	jids := make([]string, 0, len(blocklist.JIDs))
+	for _, jid := range blocklist.JIDs {
+		jids = append(jids, jid.String())
+	}
+	return jids
+}
@@ -0,0 +1,375 @@
+package whatsapp
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/appstate"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// whatsapp limits on the own profile
+const (
+	MaxPushNameLength = 25
+	MaxAboutLength    = 139
+)
+
+// ProfilePictureTTL is how long a picture is served from the cache, the urls whatsapp gives expire after a few days.
+const ProfilePictureTTL = time.Hour
+
+// pictureURLLifetime is how long a cached url is trusted to still work, an older one is fetched again
+// even when the picture did not change.
+const pictureURLLifetime = 24 * time.Hour
+
+// maxPictureCache bounds the cached pictures, unusable entries are swept first once it is reached.
+const maxPictureCache = 10_000
+
+var (
+	ErrUserNotFound    = errors.New("user is not on whatsapp")
+	ErrNotBusiness     = errors.New("user is not a business account")
+	ErrNoPicture       = errors.New("user or group does not have a profile picture")
+	ErrPictureHidden   = errors.New("profile picture is hidden from the device")
+	ErrInvalidPushName = errors.New("push name must be between 1 and 25 characters")
+	ErrInvalidAbout    = errors.New("about text must be at most 139 characters")
+)
+
+type Contact struct {
+	JID          string `json:"jid"`
+	FirstName    string `json:"first_name"`
+	FullName     string `json:"full_name"`
+	PushName     string `json:"push_name"`
+	BusinessName string `json:"business_name"`
+}
+
+func NewContact(jid types.JID, info types.ContactInfo) *Contact {
+	return &Contact{
+		JID:          jid.String(),
+		FirstName:    info.FirstName,
+		FullName:     info.FullName,
+		PushName:     info.PushName,
+		BusinessName: info.BusinessName,
+	}
+}
+
+// Name is the best known name, as saved in the phone's address book first.
+func (c *Contact) Name() string {
+	for _, name := range []string{c.FullName, c.FirstName, c.BusinessName, c.PushName} {
+		if name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// Contacts returns the contacts synced from the phone sorted by name, filtered by q in any name or the number.
+func Contacts(cli *whatsmeow.Client, q string) ([]*Contact, error) {
+	all, err := cli.Store.Contacts.GetAllContacts()
+	if err != nil {
+		return nil, err
+	}
+
+	q = strings.ToLower(strings.TrimSpace(q))
+	contacts := make([]*Contact, 0, len(all))
+	for jid, info := range all {
+		contact := NewContact(jid, info)
+		if q != "" && !strings.Contains(strings.ToLower(strings.Join([]string{
+			contact.JID, contact.FirstName, contact.FullName, contact.PushName, contact.BusinessName,
+		}, "\n")), q) {
+			continue
+		}
+		contacts = append(contacts, contact)
+	}
+	sort.Slice(contacts, func(i, j int) bool {
+		a, b := strings.ToLower(contacts[i].Name()), strings.ToLower(contacts[j].Name())
+		if a != b {
+			return a < b
+		}
+		return contacts[i].JID < contacts[j].JID
+	})
+	return contacts, nil
+}
+
+type UserInfo struct {
+	Contact
+	Status       string   `json:"status"`
+	PictureID    string   `json:"picture_id"`
+	IsBusiness   bool     `json:"is_business"`
+	VerifiedName string   `json:"verified_name"`
+	Devices      []string `json:"devices"`
+}
+
+// GetUserInfo combines what whatsapp tells about the user with the names the device knows.
+func GetUserInfo(cli *whatsmeow.Client, jid types.JID) (*UserInfo, error) {
+	infos, err := cli.GetUserInfo([]types.JID{jid})
+	if err != nil {
+		return nil, err
+	}
+	info, ok := infos[jid]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+
+	contact, err := cli.Store.Contacts.GetContact(jid)
+	if err != nil {
+		return nil, err
+	}
+	user := &UserInfo{
+		Contact:   *NewContact(jid, contact),
+		Status:    info.Status,
+		PictureID: info.PictureID,
+		Devices:   jidStrings(info.Devices),
+	}
+	if info.VerifiedName != nil {
+		user.IsBusiness = true
+		user.VerifiedName = info.VerifiedName.Details.GetVerifiedName()
+	}
+	if user.Devices == nil {
+		user.Devices = []string{}
+	}
+	return user, nil
+}
+
+type BusinessHours struct {
+	DayOfWeek string `json:"day_of_week"`
+	Mode      string `json:"mode"`
+	OpenTime  string `json:"open_time"`
+	CloseTime string `json:"close_time"`
+}
+
+type BusinessProfile struct {
+	JID           string            `json:"jid"`
+	Address       string            `json:"address"`
+	Email         string            `json:"email"`
+	Categories    []string          `json:"categories"`
+	Options       map[string]string `json:"options"`
+	HoursTimezone string            `json:"hours_timezone"`
+	Hours         []BusinessHours   `json:"hours"`
+}
+
+func GetBusinessProfile(cli *whatsmeow.Client, jid types.JID) (*BusinessProfile, error) {
+	profile, err := cli.GetBusinessProfile(jid)
+	if err != nil {
+		return nil, err
+	}
+	// whatsapp answers with an empty profile for regular accounts
+	if profile == nil || profile.JID.IsEmpty() {
+		return nil, ErrNotBusiness
+	}
+
+	result := &BusinessProfile{
+		JID:           profile.JID.String(),
+		Address:       profile.Address,
+		Email:         profile.Email,
+		Categories:    make([]string, 0, len(profile.Categories)),
+		Options:       profile.ProfileOptions,
+		HoursTimezone: profile.BusinessHoursTimeZone,
+		Hours:         make([]BusinessHours, 0, len(profile.BusinessHours)),
+	}
+	for _, category := range profile.Categories {
+		result.Categories = append(result.Categories, category.Name)
+	}
+	for _, hours := range profile.BusinessHours {
+		result.Hours = append(result.Hours, BusinessHours{
+			DayOfWeek: hours.DayOfWeek,
+			Mode:      hours.Mode,
+			OpenTime:  hours.OpenTime,
+			CloseTime: hours.CloseTime,
+		})
+	}
+	return result, nil
+}
+
+type ProfilePicture struct {
+	JID       string    `json:"jid"`
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Type      string    `json:"type"`
+	Cached    bool      `json:"cached"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+type pictureEntry struct {
+	picture *ProfilePicture
+	err     error
+	// urlAt is when whatsapp issued the url of the picture
+	urlAt time.Time
+}
+
+// usable reports whether the entry still saves a request, as a fresh answer or as a url to revalidate.
+func (e *pictureEntry) usable(now time.Time) bool {
+	if e.err != nil {
+		return now.Sub(e.picture.CheckedAt) < ProfilePictureTTL
+	}
+	return now.Sub(e.urlAt) < pictureURLLifetime
+}
+
+// pictureCache keeps pictures by device, since privacy settings decide which device may see them.
+type pictureCache struct {
+	mut     sync.Mutex
+	entries map[string]*pictureEntry
+}
+
+func newPictureCache() *pictureCache {
+	return &pictureCache{entries: make(map[string]*pictureEntry)}
+}
+
+func (c *pictureCache) get(key string) *pictureEntry {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	return c.entries[key]
+}
+
+func (c *pictureCache) set(key string, entry *pictureEntry, now time.Time) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= maxPictureCache {
+		for k, e := range c.entries {
+			if !e.usable(now) {
+				delete(c.entries, k)
+			}
+		}
+		// still full of fresh entries, drop arbitrary ones
+		for k := range c.entries {
+			if len(c.entries) < maxPictureCache {
+				break
+			}
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = entry
+}
+
+func (c *pictureCache) forget(clientDeviceID string, jid types.JID) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	delete(c.entries, pictureKey(clientDeviceID, jid, false))
+	delete(c.entries, pictureKey(clientDeviceID, jid, true))
+}
+
+func pictureKey(clientDeviceID string, jid types.JID, preview bool) string {
+	if preview {
+		return clientDeviceID + "|" + jid.String() + "|preview"
+	}
+	return clientDeviceID + "|" + jid.String() + "|image"
+}
+
+// ProfilePicture returns the picture of a user or group, the full size one or its preview.
+// Within ProfilePictureTTL it comes from the cache, missing and hidden pictures included.
+// After that whatsapp is asked whether it changed, so an unchanged picture costs no new url,
+// unless the cached url is older than pictureURLLifetime and may have expired.
+func (c *Client) ProfilePicture(cli *whatsmeow.Client, clientDeviceID string, jid types.JID, preview bool) (*ProfilePicture, error) {
+	key := pictureKey(clientDeviceID, jid, preview)
+
+	now := time.Now().UTC()
+	cached := c.pictures.get(key)
+	if cached != nil && cached.err == nil && now.Sub(cached.picture.CheckedAt) < ProfilePictureTTL {
+		picture := *cached.picture
+		picture.Cached = true
+		return &picture, nil
+	}
+	if cached != nil && cached.err != nil && now.Sub(cached.picture.CheckedAt) < ProfilePictureTTL {
+		return nil, cached.err
+	}
+
+	params := &whatsmeow.GetProfilePictureParams{Preview: preview}
+	if cached != nil && cached.err == nil && cached.usable(now) {
+		params.ExistingID = cached.picture.ID
+	}
+	info, err := cli.GetProfilePictureInfo(jid, params)
+	switch {
+	case errors.Is(err, whatsmeow.ErrProfilePictureNotSet):
+		err = ErrNoPicture
+	case errors.Is(err, whatsmeow.ErrProfilePictureUnauthorized):
+		err = ErrPictureHidden
+	case err != nil:
+		return nil, err
+	}
+	if err == nil && info == nil && params.ExistingID == "" {
+		err = ErrNoPicture
+	}
+	if err != nil {
+		c.pictures.set(key, &pictureEntry{picture: &ProfilePicture{JID: jid.String(), CheckedAt: now}, err: err}, now)
+		return nil, err
+	}
+
+	var picture *ProfilePicture
+	urlAt := now
+	if info == nil {
+		// unchanged since the cached one
+		unchanged := *cached.picture
+		unchanged.CheckedAt = now
+		picture = &unchanged
+		urlAt = cached.urlAt
+	} else {
+		picture = &ProfilePicture{
+			JID:       jid.String(),
+			ID:        info.ID,
+			URL:       info.URL,
+			Type:      info.Type,
+			CheckedAt: now,
+		}
+	}
+	c.pictures.set(key, &pictureEntry{picture: picture, urlAt: urlAt}, now)
+	result := *picture
+	return &result, nil
+}
+
+// OwnProfile is what other users see of the device.
+type OwnProfile struct {
+	JID       string `json:"jid"`
+	PushName  string `json:"push_name"`
+	About     string `json:"about"`
+	PictureID string `json:"picture_id"`
+}
+
+func GetOwnProfile(cli *whatsmeow.Client) (*OwnProfile, error) {
+	own := cli.Store.ID.ToNonAD()
+	profile := &OwnProfile{JID: own.String(), PushName: cli.Store.PushName}
+	infos, err := cli.GetUserInfo([]types.JID{own})
+	if err != nil {
+		return nil, err
+	}
+	if info, ok := infos[own]; ok {
+		profile.About = info.Status
+		profile.PictureID = info.PictureID
+	}
+	return profile, nil
+}
+
+// SetPushName changes the name shown to users who did not save the number, it syncs to the phone as well.
+func SetPushName(cli *whatsmeow.Client, name string) error {
+	name = strings.TrimSpace(name)
+	if n := len([]rune(name)); n == 0 || n > MaxPushNameLength {
+		return ErrInvalidPushName
+	}
+	if err := cli.SendAppState(appstate.BuildSettingPushName(name)); err != nil {
+		return err
+	}
+	// whatsapp does not echo the patch back to the device that sent it
+	cli.Store.PushName = name
+	return cli.Store.Save()
+}
+
+func SetAbout(cli *whatsmeow.Client, about string) error {
+	if len([]rune(about)) > MaxAboutLength {
+		return ErrInvalidAbout
+	}
+	return cli.SetStatusMessage(about)
+}
+
+// SetProfilePicture changes the picture of the device, nil removes it. The picture has to be a jpeg,
+// see avatar.Square. It returns the new picture id.
+func (c *Client) SetProfilePicture(cli *whatsmeow.Client, clientDeviceID string, jpeg []byte) (string, error) {
+	// the empty jid targets the own profile
+	id, err := cli.SetGroupPhoto(types.EmptyJID, jpeg)
+	if err != nil {
+		return "", err
+	}
+	c.pictures.forget(clientDeviceID, cli.Store.ID.ToNonAD())
+	return id, nil
+}
@@ -0,0 +1,245 @@
+package whatsapp
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+const (
+	EventMessageReceived = "message.received"
+	EventMessageReceipt  = "message.receipt"
+)
+
+const (
+	ReceiptDelivered = "delivered"
+	ReceiptRead      = "read"
+	ReceiptPlayed    = "played"
+)
+
+var ErrNoMessageIDs = errors.New("message_ids must not be empty")
+
+// ReadResult tells which messages got a receipt, Skipped are own messages and unknown group messages
+// whose sender is needed for the receipt.
+type ReadResult struct {
+	Read    []string `json:"read"`
+	Played  []string `json:"played"`
+	Skipped []string `json:"skipped"`
+}
+
+// MarkRead sends read receipts for the incoming messages of the chat, and played receipts for its voice notes
+// when played is set. Whatsapp takes one receipt per sender, so group messages are grouped by who sent them.
+func (c *Client) MarkRead(cli *whatsmeow.Client, clientDeviceID string, chat types.JID, ids []string, played bool) (*ReadResult, error) {
+	if len(ids) == 0 {
+		return nil, ErrNoMessageIDs
+	}
+	chat = chat.ToNonAD()
+	stored, err := c.messages.GetMessages(clientDeviceID, chat.String(), ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReadResult{Read: []string{}, Played: []string{}, Skipped: []string{}}
+	var senders []types.JID
+	read := make(map[types.JID][]types.MessageID)
+	audio := make(map[types.JID][]types.MessageID)
+	for _, id := range ids {
+		sender := chat
+		m, ok := stored[id]
+		switch {
+		case ok && m.FromMe:
+			result.Skipped = append(result.Skipped, id)
+			continue
+		case ok:
+			if sender, err = types.ParseJID(m.SenderJID); err != nil {
+				return nil, err
+			}
+		case chat.Server != types.DefaultUserServer:
+			result.Skipped = append(result.Skipped, id)
+			continue
+		}
+		if _, ok := read[sender]; !ok {
+			senders = append(senders, sender)
+		}
+		read[sender] = append(read[sender], id)
+		if played && ok && m.Type == MessageTypeAudio {
+			audio[sender] = append(audio[sender], id)
+		}
+	}
+
+	now := time.Now()
+	for _, sender := range senders {
+		if err = cli.MarkRead(read[sender], now, chat, sender); err != nil {
+			return nil, err
+		}
+		result.Read = append(result.Read, read[sender]...)
+		if len(audio[sender]) == 0 {
+			continue
+		}
+		if err = cli.MarkRead(audio[sender], now, chat, sender, types.ReceiptTypePlayed); err != nil {
+			return nil, err
+		}
+		result.Played = append(result.Played, audio[sender]...)
+	}
+	return result, nil
+}
+
+// Ack marks the messages a consumer handled as read when the device has auto_mark_read enabled.
+// Otherwise nothing is sent or stored and nil is returned.
+func (c *Client) Ack(cli *whatsmeow.Client, clientDeviceID string, chat types.JID, ids []string) (*ReadResult, error) {
+	if len(ids) == 0 {
+		return nil, ErrNoMessageIDs
+	}
+	settings, err := c.settings.Get(clientDeviceID)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.AutoMarkRead {
+		return nil, nil
+	}
+	return c.MarkRead(cli, clientDeviceID, chat, ids, false)
+}
+
+// Receipt is how far one recipient got with a message we sent, in groups every member has their own.
+type Receipt struct {
+	RecipientJID string     `json:"recipient_jid"`
+	Status       string     `json:"status"`
+	DeliveredAt  *time.Time `json:"delivered_at"`
+	ReadAt       *time.Time `json:"read_at"`
+	PlayedAt     *time.Time `json:"played_at"`
+}
+
+type ReceiptEvent struct {
+	ChatJID      string    `json:"chat_jid"`
+	RecipientJID string    `json:"recipient_jid"`
+	MessageIDs   []string  `json:"message_ids"`
+	Status       string    `json:"status"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+type ReceiptRepo struct {
+	db *sql.DB
+}
+
+// Save records the receipt of every message, the first time a status is reached is kept.
+func (r *ReceiptRepo) Save(clientDeviceID string, evt *ReceiptEvent) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, id := range evt.MessageIDs {
+		_, err = tx.Exec(`INSERT INTO
+			whatsmeow_extended_receipt (
+				client_device_id,
+				chat_jid,
+				message_id,
+				recipient_jid,
+				delivered_at,
+				read_at,
+				played_at
+			)
+			VALUES ($1, $2, $3, $4,
+				CASE WHEN $5 = 'delivered' THEN $6::TIMESTAMPTZ END,
+				CASE WHEN $5 = 'read' THEN $6::TIMESTAMPTZ END,
+				CASE WHEN $5 = 'played' THEN $6::TIMESTAMPTZ END)
+			ON CONFLICT (client_device_id, chat_jid, message_id, recipient_jid) DO UPDATE SET
+				delivered_at = COALESCE(whatsmeow_extended_receipt.delivered_at, EXCLUDED.delivered_at),
+				read_at = COALESCE(whatsmeow_extended_receipt.read_at, EXCLUDED.read_at),
+				played_at = COALESCE(whatsmeow_extended_receipt.played_at, EXCLUDED.played_at)`,
+			clientDeviceID,
+			evt.ChatJID,
+			id,
+			evt.RecipientJID,
+			evt.Status,
+			evt.Timestamp,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// List returns the receipts of a message by recipient.
+func (r *ReceiptRepo) List(clientDeviceID string, chatJID string, messageID string) ([]*Receipt, error) {
+	rows, err := r.db.Query(`SELECT recipient_jid, delivered_at, read_at, played_at
+		FROM whatsmeow_extended_receipt
+		WHERE client_device_id = $1 AND chat_jid = $2 AND message_id = $3
+		ORDER BY recipient_jid`,
+		clientDeviceID,
+		chatJID,
+		messageID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	receipts := make([]*Receipt, 0)
+	for rows.Next() {
+		var i Receipt
+		err := rows.Scan(
+			&i.RecipientJID,
+			&i.DeliveredAt,
+			&i.ReadAt,
+			&i.PlayedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		i.Status = receiptStatus(&i)
+		receipts = append(receipts, &i)
+	}
+	return receipts, rows.Err()
+}
+
+// receiptStatus is the furthest status, a read receipt may arrive without a delivered one.
+func receiptStatus(r *Receipt) string {
+	switch {
+	case r.PlayedAt != nil:
+		return ReceiptPlayed
+	case r.ReadAt != nil:
+		return ReceiptRead
+	}
+	return ReceiptDelivered
+}
+
+func (c *Client) Receipts() *ReceiptRepo {
+	return c.receipts
+}
+
+// handleReceipt tracks what the recipients did with our messages, receipts of our own other devices are ignored.
+func (c *Client) handleReceipt(clientDeviceID string, evt *events.Receipt) {
+	if evt.IsFromMe {
+		return
+	}
+	var status string
+	switch evt.Type {
+	case types.ReceiptTypeDelivered:
+		status = ReceiptDelivered
+	case types.ReceiptTypeRead:
+		status = ReceiptRead
+	case types.ReceiptTypePlayed:
+		status = ReceiptPlayed
+	default:
+		return
+	}
+
+	data := &ReceiptEvent{
+		ChatJID:      evt.Chat.ToNonAD().String(),
+		RecipientJID: evt.Sender.ToNonAD().String(),
+		MessageIDs:   evt.MessageIDs,
+		Status:       status,
+		Timestamp:    evt.Timestamp.UTC(),
+	}
+	if err := c.receipts.Save(clientDeviceID, data); err != nil {
+		c.log.Errorf("cannot store %v receipt of %v for client id: %v, %v", status, evt.MessageIDs, clientDeviceID, err)
+	}
+	c.publish(clientDeviceID, EventMessageReceipt, data)
+}
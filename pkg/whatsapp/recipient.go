@@ -0,0 +1,249 @@
+package whatsapp
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/hrz8/whatsapp-api/pkg/phone"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+type RecipientType string
+
+const (
+	RecipientPhone      RecipientType = "phone"
+	RecipientGroup      RecipientType = "group"
+	RecipientCommunity  RecipientType = "community"
+	RecipientNewsletter RecipientType = "newsletter"
+)
+
+var (
+	ErrInvalidRecipientType = errors.New("recipient type must be one of phone, group, community or newsletter")
+	ErrInvalidRecipient     = errors.New("recipient is not valid for its type")
+	ErrNotGroupMember       = errors.New("device is not a member of the recipient group")
+	ErrGroupAdminsOnly      = errors.New("only admins can send messages to the recipient group")
+	ErrNotNewsletterAdmin   = errors.New("only newsletter owners and admins can send messages to it")
+	ErrMentionAllNotGroup   = errors.New("mention all is only supported for group recipients")
+)
+
+// Recipient is a validated message target, Group is set for group recipients once resolved.
+// Community recipients resolve to the community announcement group.
+type Recipient struct {
+	Type  RecipientType
+	JID   types.JID
+	Group *types.GroupInfo
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// ParseRecipient validates the recipient against its type. Without a type it is inferred from the JID server,
+// plain numbers being phone numbers, which are normalized with defaultCountry (see phone.Normalize).
+func ParseRecipient(typ RecipientType, arg string, defaultCountry string) (*Recipient, error) {
+	arg = strings.TrimSpace(arg)
+	if arg == "" {
+		return nil, ErrInvalidRecipient
+	}
+	if typ == "" {
+		switch {
+		case strings.HasSuffix(arg, "@"+types.GroupServer):
+			typ = RecipientGroup
+		case strings.HasSuffix(arg, "@"+types.NewsletterServer):
+			typ = RecipientNewsletter
+		default:
+			typ = RecipientPhone
+		}
+	}
+
+	server := ""
+	switch typ {
+	case RecipientPhone:
+		if !strings.ContainsRune(arg, '@') {
+			number, err := phone.Normalize(arg, defaultCountry)
+			if err != nil {
+				return nil, err
+			}
+			return &Recipient{Type: typ, JID: types.NewJID(number, types.DefaultUserServer)}, nil
+		}
+		server = types.DefaultUserServer
+	case RecipientGroup, RecipientCommunity:
+		server = types.GroupServer
+	case RecipientNewsletter:
+		server = types.NewsletterServer
+	default:
+		return nil, ErrInvalidRecipientType
+	}
+	arg = strings.TrimPrefix(arg, "+")
+	if !strings.ContainsRune(arg, '@') {
+		arg += "@" + server
+	}
+
+	jid, err := types.ParseJID(arg)
+	if err != nil || jid.Server != server {
+		return nil, ErrInvalidRecipient
+	}
+	switch typ {
+	case RecipientPhone:
+		if !isDigits(jid.User) || len(jid.User) < phone.MinLength || len(jid.User) > phone.MaxLength {
+			return nil, ErrInvalidRecipient
+		}
+	case RecipientGroup, RecipientCommunity:
+		// groups are either "<creator>-<timestamp>" or a plain id
+		if !isDigits(strings.ReplaceAll(jid.User, "-", "")) {
+			return nil, ErrInvalidRecipient
+		}
+	case RecipientNewsletter:
+		if !isDigits(jid.User) {
+			return nil, ErrInvalidRecipient
+		}
+	}
+	return &Recipient{Type: typ, JID: jid.ToNonAD()}, nil
+}
+
+// ResolveRecipient parses the recipient and checks it can receive messages from the device:
+// phone numbers have to be on whatsapp, groups need the device as a member (and admin in announce groups),
+// newsletters need the device as owner or admin, communities are sent to through their announcement group.
+func (c *Client) ResolveRecipient(cli *whatsmeow.Client, clientDeviceID string, typ RecipientType, arg string) (*Recipient, error) {
+	rcpt, err := ParseRecipient(typ, arg, c.DefaultCountry(clientDeviceID))
+	if err != nil {
+		return nil, err
+	}
+
+	switch rcpt.Type {
+	case RecipientPhone:
+		checks, err := c.CheckContacts(cli, []string{rcpt.JID.User}, "")
+		if err != nil {
+			return nil, err
+		}
+		if checks[0].Status != ContactRegistered {
+			return nil, ErrRecipientNotFound
+		}
+		// the canonical JID can differ from the number, e.g. for brazilian numbers without the ninth digit
+		jid, err := types.ParseJID(checks[0].JID)
+		if err != nil {
+			return nil, ErrRecipientNotFound
+		}
+		rcpt.JID = jid
+	case RecipientCommunity:
+		announcement, err := AnnouncementGroup(cli, rcpt.JID)
+		if errors.Is(err, whatsmeow.ErrGroupNotFound) {
+			return nil, ErrRecipientNotFound
+		}
+		if errors.Is(err, whatsmeow.ErrNotInGroup) {
+			return nil, ErrNotGroupMember
+		}
+		if err != nil {
+			return nil, err
+		}
+		rcpt.JID = announcement
+		if err = resolveGroup(cli, rcpt); err != nil {
+			return nil, err
+		}
+	case RecipientGroup:
+		if err = resolveGroup(cli, rcpt); err != nil {
+			return nil, err
+		}
+	case RecipientNewsletter:
+		info, err := cli.GetNewsletterInfo(rcpt.JID)
+		if err != nil || info == nil {
+			return nil, ErrRecipientNotFound
+		}
+		if info.ViewerMeta == nil ||
+			(info.ViewerMeta.Role != types.NewsletterRoleOwner && info.ViewerMeta.Role != types.NewsletterRoleAdmin) {
+			return nil, ErrNotNewsletterAdmin
+		}
+	}
+	return rcpt, nil
+}
+
+func resolveGroup(cli *whatsmeow.Client, rcpt *Recipient) error {
+	info, err := cli.GetGroupInfo(rcpt.JID)
+	if errors.Is(err, whatsmeow.ErrNotInGroup) {
+		return ErrNotGroupMember
+	}
+	if errors.Is(err, whatsmeow.ErrGroupNotFound) {
+		return ErrRecipientNotFound
+	}
+	if err != nil {
+		return err
+	}
+	member := ownParticipant(cli, info)
+	if member == nil {
+		return ErrNotGroupMember
+	}
+	if info.IsAnnounce && !member.IsAdmin && !member.IsSuperAdmin {
+		return ErrGroupAdminsOnly
+	}
+	rcpt.Group = info
+	return nil
+}
+
+func ownParticipant(cli *whatsmeow.Client, info *types.GroupInfo) *types.GroupParticipant {
+	if cli.Store.ID == nil {
+		return nil
+	}
+	own := cli.Store.ID.ToNonAD()
+	for i, p := range info.Participants {
+		if p.JID.ToNonAD() == own {
+			return &info.Participants[i]
+		}
+	}
+	return nil
+}
+
+// Mentions returns every group participant except the device itself.
+func (r *Recipient) Mentions(cli *whatsmeow.Client) []string {
+	if r.Group == nil {
+		return nil
+	}
+	var own types.JID
+	if cli.Store.ID != nil {
+		own = cli.Store.ID.ToNonAD()
+	}
+	jids := make([]string, 0, len(r.Group.Participants))
+	for _, p := range r.Group.Participants {
+		if p.JID.ToNonAD() != own {
+			jids = append(jids, p.JID.ToNonAD().String())
+		}
+	}
+	return jids
+}
+
+// MentionAll mentions the given JIDs without writing them in the text, every one of them gets notified.
+func MentionAll(msg *waE2E.Message, jids []string) *waE2E.Message {
+	mention := func(ctxInfo *waE2E.ContextInfo) *waE2E.ContextInfo {
+		if ctxInfo == nil {
+			ctxInfo = &waE2E.ContextInfo{}
+		}
+		ctxInfo.MentionedJID = jids
+		return ctxInfo
+	}
+
+	switch {
+	case msg.GetConversation() != "":
+		return &waE2E.Message{ExtendedTextMessage: &waE2E.ExtendedTextMessage{
+			Text:        proto.String(msg.GetConversation()),
+			ContextInfo: mention(nil),
+		}}
+	case msg.GetExtendedTextMessage() != nil:
+		msg.ExtendedTextMessage.ContextInfo = mention(msg.ExtendedTextMessage.ContextInfo)
+	case msg.GetImageMessage() != nil:
+		msg.ImageMessage.ContextInfo = mention(msg.ImageMessage.ContextInfo)
+	case msg.GetVideoMessage() != nil:
+		msg.VideoMessage.ContextInfo = mention(msg.VideoMessage.ContextInfo)
+	case msg.GetAudioMessage() != nil:
+		msg.AudioMessage.ContextInfo = mention(msg.AudioMessage.ContextInfo)
+	case msg.GetDocumentMessage() != nil:
+		msg.DocumentMessage.ContextInfo = mention(msg.DocumentMessage.ContextInfo)
+	}
+	return msg
+}
@@ -0,0 +1,134 @@
+package whatsapp
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrSearchQueryEmpty = errors.New("search query is empty")
+	ErrInvalidCursor    = errors.New("search cursor is invalid")
+)
+
+// MessageSearch filters the full-text search, every field except Query is optional.
+type MessageSearch struct {
+	ClientDeviceID string
+	Query          string
+	ChatJID        string
+	SenderJID      string
+	Type           string
+	From           time.Time
+	To             time.Time
+	Cursor         string
+	Limit          int
+}
+
+type MessageSearchResult struct {
+	*Message
+	Snippet string `json:"snippet"`
+}
+
+// encodeCursor points right after the given message in the (timestamp, id) descending order.
+func encodeCursor(m *Message) string {
+	raw := fmt.Sprintf("%d:%d", m.Timestamp.UnixNano(), m.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (time.Time, int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+	ts, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+	nanos, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+	msgID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+	return time.Unix(0, nanos), msgID, nil
+}
+
+// Search runs a full-text search over stored messages, newest first, with the matches highlighted in the snippet.
+// The returned cursor fetches the next page and is empty on the last one.
+func (r *MessageRepo) Search(s *MessageSearch) ([]*MessageSearchResult, string, error) {
+	if strings.TrimSpace(s.Query) == "" {
+		return nil, "", ErrSearchQueryEmpty
+	}
+
+	args := []any{s.Query}
+	conds := []string{"text_search @@ websearch_to_tsquery('simple', $1)"}
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	if s.ClientDeviceID != "" {
+		add("client_device_id = $%d", s.ClientDeviceID)
+	}
+	if s.ChatJID != "" {
+		add("chat_jid = $%d", s.ChatJID)
+	}
+	if s.SenderJID != "" {
+		add("sender_jid = $%d", s.SenderJID)
+	}
+	if s.Type != "" {
+		add("type = $%d", s.Type)
+	}
+	if !s.From.IsZero() {
+		add("timestamp >= $%d", s.From)
+	}
+	if !s.To.IsZero() {
+		add("timestamp < $%d", s.To)
+	}
+	if s.Cursor != "" {
+		ts, id, err := decodeCursor(s.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		args = append(args, ts, id)
+		conds = append(conds, fmt.Sprintf("(timestamp, id) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+	args = append(args, s.Limit+1)
+
+	rows, err := r.db.Query(`SELECT `+messageColumns+`,
+			ts_headline('simple', text, websearch_to_tsquery('simple', $1), 'StartSel=**, StopSel=**, MaxFragments=2, MaxWords=20, MinWords=5')
+		FROM whatsmeow_extended_message
+		WHERE `+strings.Join(conds, " AND ")+`
+		ORDER BY timestamp DESC, id DESC
+		LIMIT $`+strconv.Itoa(len(args)),
+		args...,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	results := make([]*MessageSearchResult, 0)
+	for rows.Next() {
+		var snippet string
+		m, err := scanMessage(rows, &snippet)
+		if err != nil {
+			return nil, "", err
+		}
+		results = append(results, &MessageSearchResult{m, snippet})
+	}
+	if err = rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	next := ""
+	if len(results) > s.Limit {
+		results = results[:s.Limit]
+		next = encodeCursor(results[len(results)-1].Message)
+	}
+	return results, next, nil
+}
@@ -0,0 +1,39 @@
+package whatsapp
+
+import (
+	"context"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// IsSuppressed reports whether the recipient opted out of messages from the device. Only users can opt out,
+// callers doing anything visible to the recipient before SendMessage (like typing) check it first.
+func (c *Client) IsSuppressed(clientDeviceID string, to types.JID) (bool, error) {
+	// users are addressed by phone number or by their hidden LID, opt-outs are stored in the form the user wrote from
+	if to.Server != types.DefaultUserServer && to.Server != types.HiddenUserServer {
+		return false, nil
+	}
+	return c.suppressions.IsSuppressed(clientDeviceID, to.ToNonAD().String())
+}
+
+// SendMessage is the single path every outgoing message has to go through,
+// so rules like the suppression list are applied and the message is stored regardless of who is sending.
+func (c *Client) SendMessage(ctx context.Context, cli *whatsmeow.Client, clientDeviceID string, to types.JID, msg *waE2E.Message, extra ...whatsmeow.SendRequestExtra) (resp whatsmeow.SendResponse, err error) {
+	suppressed, err := c.IsSuppressed(clientDeviceID, to)
+	if err != nil {
+		return resp, err
+	}
+	if suppressed {
+		c.log.Warnf("refusing to send message to suppressed %v for client id: %v", to, clientDeviceID)
+		return resp, ErrRecipientSuppressed
+	}
+
+	resp, err = cli.SendMessage(ctx, to, msg, extra...)
+	if err != nil {
+		return resp, err
+	}
+	c.storeSentMessage(cli, clientDeviceID, to, msg, resp)
+	return resp, nil
+}
@@ -0,0 +1,80 @@
+package whatsapp
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// DeviceSettings holds per device behaviour, a device without a row has the zero settings.
+type DeviceSettings struct {
+	ClientDeviceID string `json:"client_device_id"`
+	// replaces the trunk prefix of national numbers like "0812...", falls back to the client wide default
+	DefaultCountryCode string `json:"default_country_code"`
+	// marks incoming messages as read once an event consumer acknowledged them
+	AutoMarkRead bool      `json:"auto_mark_read"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type SettingsRepo struct {
+	db *sql.DB
+}
+
+func (r *SettingsRepo) Get(clientDeviceID string) (*DeviceSettings, error) {
+	row := r.db.QueryRow(`SELECT client_device_id, default_country_code, auto_mark_read, updated_at
+		FROM whatsmeow_extended_device_settings
+		WHERE client_device_id = $1`,
+		clientDeviceID,
+	)
+	var i DeviceSettings
+	err := row.Scan(
+		&i.ClientDeviceID,
+		&i.DefaultCountryCode,
+		&i.AutoMarkRead,
+		&i.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &DeviceSettings{ClientDeviceID: clientDeviceID}, nil
+	}
+	return &i, err
+}
+
+func (r *SettingsRepo) Save(s *DeviceSettings) (*DeviceSettings, error) {
+	row := r.db.QueryRow(`INSERT INTO
+		whatsmeow_extended_device_settings (
+			client_device_id,
+			default_country_code,
+			auto_mark_read
+		)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (client_device_id) DO UPDATE SET
+			default_country_code = EXCLUDED.default_country_code,
+			auto_mark_read = EXCLUDED.auto_mark_read,
+			updated_at = now()
+		RETURNING client_device_id, default_country_code, auto_mark_read, updated_at`,
+		s.ClientDeviceID,
+		s.DefaultCountryCode,
+		s.AutoMarkRead,
+	)
+	var i DeviceSettings
+	err := row.Scan(
+		&i.ClientDeviceID,
+		&i.DefaultCountryCode,
+		&i.AutoMarkRead,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+// DefaultCountry returns the country code national numbers of the device are completed with.
+func (c *Client) DefaultCountry(clientDeviceID string) string {
+	settings, err := c.settings.Get(clientDeviceID)
+	if err != nil {
+		c.log.Errorf("cannot get settings for client id: %v, %v", clientDeviceID, err)
+		return c.defaultCountry
+	}
+	if settings.DefaultCountryCode != "" {
+		return settings.DefaultCountryCode
+	}
+	return c.defaultCountry
+}
@@ -0,0 +1,134 @@
+package whatsapp
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// GlobalSuppression is the client device id used for entries that apply to every device.
+const GlobalSuppression = ""
+
+var (
+	ErrRecipientSuppressed = errors.New("recipient has opted out from receiving messages")
+	ErrSuppressionNotFound = errors.New("suppression entry not found")
+)
+
+var DefaultOptOutKeywords = []string{"STOP", "UNSUBSCRIBE"}
+
+type Suppression struct {
+	ID             int       `json:"id"`
+	ClientDeviceID string    `json:"client_device_id"`
+	JID            string    `json:"jid"`
+	Reason         string    `json:"reason"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type SuppressionRepo struct {
+	db *sql.DB
+}
+
+func (r *SuppressionRepo) Add(clientDeviceID string, jid string, reason string) (*Suppression, error) {
+	row := r.db.QueryRow(`INSERT INTO
+		whatsmeow_extended_suppression (
+			client_device_id,
+			jid,
+			reason
+		)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (client_device_id, jid) DO UPDATE SET reason = EXCLUDED.reason
+		RETURNING id, client_device_id, jid, reason, created_at`,
+		clientDeviceID,
+		jid,
+		reason,
+	)
+	var i Suppression
+	err := row.Scan(
+		&i.ID,
+		&i.ClientDeviceID,
+		&i.JID,
+		&i.Reason,
+		&i.CreatedAt,
+	)
+	return &i, err
+}
+
+func (r *SuppressionRepo) Remove(clientDeviceID string, jid string) error {
+	res, err := r.db.Exec("DELETE FROM whatsmeow_extended_suppression WHERE client_device_id = $1 AND jid = $2", clientDeviceID, jid)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err == nil && n == 0 {
+		return ErrSuppressionNotFound
+	}
+	return err
+}
+
+// List returns the entries of a device together with the global ones.
+func (r *SuppressionRepo) List(clientDeviceID string) ([]*Suppression, error) {
+	rows, err := r.db.Query(`SELECT id, client_device_id, jid, reason, created_at
+		FROM whatsmeow_extended_suppression
+		WHERE client_device_id IN ($1, $2)
+		ORDER BY created_at DESC`,
+		clientDeviceID,
+		GlobalSuppression,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]*Suppression, 0)
+	for rows.Next() {
+		var i Suppression
+		if err := rows.Scan(&i.ID, &i.ClientDeviceID, &i.JID, &i.Reason, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	return items, rows.Err()
+}
+
+func (r *SuppressionRepo) IsSuppressed(clientDeviceID string, jid string) (bool, error) {
+	row := r.db.QueryRow(`SELECT EXISTS (
+		SELECT 1 FROM whatsmeow_extended_suppression
+		WHERE client_device_id IN ($1, $2) AND jid = $3
+	)`,
+		clientDeviceID,
+		GlobalSuppression,
+		jid,
+	)
+	var suppressed bool
+	err := row.Scan(&suppressed)
+	return suppressed, err
+}
+
+func (c *Client) isOptOut(text string) bool {
+	text = strings.TrimSpace(text)
+	for _, keyword := range c.optOutKeywords {
+		if strings.EqualFold(text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Client) handleOptOut(clientDeviceID string, evt *events.Message) {
+	if evt.Info.IsFromMe || evt.Info.IsGroup || strings.Contains(evt.Info.SourceString(), "broadcast") {
+		return
+	}
+	text := MessageText(evt.Message)
+	if !c.isOptOut(text) {
+		return
+	}
+
+	jid := evt.Info.Sender.ToNonAD().String()
+	c.log.Infof("suppressing %v for client id: %v after opt-out", jid, clientDeviceID)
+	if _, err := c.suppressions.Add(clientDeviceID, jid, "opt-out: "+strings.TrimSpace(text)); err != nil {
+		c.log.Errorf("cannot suppress %v for client id: %v, %v", jid, clientDeviceID, err)
+	}
+}
@@ -0,0 +1,255 @@
+package whatsapp
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const DefaultTemplateLanguage = "en"
+
+var (
+	ErrTemplateNotFound        = errors.New("template not found")
+	ErrTemplateAlreadyExist    = errors.New("template with specific name and language already exist")
+	ErrTemplateInvalid         = errors.New("template is invalid")
+	ErrTemplateVariableMissing = errors.New("template variable is missing")
+)
+
+var (
+	placeholderRegex  = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+	variableNameRegex = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+)
+
+type TemplateVariable struct {
+	Name     string `json:"name"`
+	Default  string `json:"default"`
+	Required bool   `json:"required"`
+}
+
+type Template struct {
+	ID        int                `json:"id"`
+	Name      string             `json:"name"`
+	Language  string             `json:"language"`
+	Body      string             `json:"body"`
+	Variables []TemplateVariable `json:"variables"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// TemplateError carries the offending variable names so the caller can report them back.
+type TemplateError struct {
+	E     error
+	Names []string
+}
+
+func (e *TemplateError) Error() string {
+	return fmt.Sprintf("%s: %s", e.E.Error(), strings.Join(e.Names, ", "))
+}
+
+func (e *TemplateError) Unwrap() error {
+	return e.E
+}
+
+// Placeholders returns the unique placeholder names used in the template body, in order of appearance.
+func (t *Template) Placeholders() []string {
+	seen := make(map[string]bool)
+	names := make([]string, 0)
+	for _, m := range placeholderRegex.FindAllStringSubmatch(t.Body, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
+
+func (t *Template) Validate() error {
+	if t.Name == "" || t.Body == "" {
+		return ErrTemplateInvalid
+	}
+	if t.Language == "" {
+		t.Language = DefaultTemplateLanguage
+	}
+
+	declared := make(map[string]bool)
+	invalid := make([]string, 0)
+	for _, v := range t.Variables {
+		if !variableNameRegex.MatchString(v.Name) || declared[v.Name] {
+			invalid = append(invalid, v.Name)
+			continue
+		}
+		declared[v.Name] = true
+	}
+	if len(invalid) > 0 {
+		return &TemplateError{ErrTemplateInvalid, invalid}
+	}
+	return nil
+}
+
+// Render substitutes every placeholder with the given variables, falling back to the declared default.
+// Placeholders which are required or not declared at all must be resolved, otherwise a TemplateError is returned.
+func (t *Template) Render(vars map[string]string) (string, error) {
+	declared := make(map[string]TemplateVariable)
+	for _, v := range t.Variables {
+		declared[v.Name] = v
+	}
+
+	values := make(map[string]string)
+	missing := make([]string, 0)
+	for _, name := range t.Placeholders() {
+		val, ok := vars[name]
+		decl, isDeclared := declared[name]
+		if !ok || val == "" {
+			val = decl.Default
+		}
+		if val == "" && (decl.Required || !isDeclared) {
+			missing = append(missing, name)
+			continue
+		}
+		values[name] = val
+	}
+	if len(missing) > 0 {
+		return "", &TemplateError{ErrTemplateVariableMissing, missing}
+	}
+
+	return placeholderRegex.ReplaceAllStringFunc(t.Body, func(s string) string {
+		return values[placeholderRegex.FindStringSubmatch(s)[1]]
+	}), nil
+}
+
+type TemplateRepo struct {
+	db *sql.DB
+}
+
+func (r *TemplateRepo) scan(row interface{ Scan(...any) error }) (*Template, error) {
+	var i Template
+	var vars []byte
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Language,
+		&i.Body,
+		&vars,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(vars, &i.Variables)
+	return &i, err
+}
+
+func (r *TemplateRepo) Create(t *Template) error {
+	vars, err := json.Marshal(t.Variables)
+	if err != nil {
+		return err
+	}
+	row := r.db.QueryRow(`INSERT INTO
+		whatsmeow_extended_template (
+			name,
+			language,
+			body,
+			variables
+		)
+		VALUES ($1, $2, $3, $4) RETURNING id, created_at, updated_at`,
+		t.Name,
+		t.Language,
+		t.Body,
+		string(vars),
+	)
+	err = row.Scan(&t.ID, &t.CreatedAt, &t.UpdatedAt)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrTemplateAlreadyExist
+	}
+	return err
+}
+
+// Get returns the template variant for the given language, falling back to DefaultTemplateLanguage.
+func (r *TemplateRepo) Get(name string, language string) (*Template, error) {
+	if language == "" {
+		language = DefaultTemplateLanguage
+	}
+	row := r.db.QueryRow(`SELECT id, name, language, body, variables, created_at, updated_at
+		FROM whatsmeow_extended_template
+		WHERE name = $1 AND language IN ($2, $3)
+		ORDER BY language = $2 DESC
+		LIMIT 1`,
+		name,
+		language,
+		DefaultTemplateLanguage,
+	)
+	t, err := r.scan(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTemplateNotFound
+	}
+	return t, err
+}
+
+func (r *TemplateRepo) List(name string) ([]*Template, error) {
+	rows, err := r.db.Query(`SELECT id, name, language, body, variables, created_at, updated_at
+		FROM whatsmeow_extended_template
+		WHERE $1 = '' OR name = $1
+		ORDER BY name, language`,
+		name,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := make([]*Template, 0)
+	for rows.Next() {
+		t, err := r.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	return templates, rows.Err()
+}
+
+func (r *TemplateRepo) Update(t *Template) error {
+	vars, err := json.Marshal(t.Variables)
+	if err != nil {
+		return err
+	}
+	row := r.db.QueryRow(`UPDATE whatsmeow_extended_template
+		SET body = $3, variables = $4, updated_at = now()
+		WHERE name = $1 AND language = $2
+		RETURNING id, created_at, updated_at`,
+		t.Name,
+		t.Language,
+		t.Body,
+		string(vars),
+	)
+	err = row.Scan(&t.ID, &t.CreatedAt, &t.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrTemplateNotFound
+	}
+	return err
+}
+
+// Delete removes a single language variant, or every variant of the template when language is empty.
+func (r *TemplateRepo) Delete(name string, language string) error {
+	res, err := r.db.Exec(`DELETE FROM whatsmeow_extended_template
+		WHERE name = $1 AND ($2 = '' OR language = $2)`,
+		name,
+		language,
+	)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err == nil && n == 0 {
+		return ErrTemplateNotFound
+	}
+	return err
+}
@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"strings"
 
-	"go.mau.fi/whatsmeow"
+	"github.com/hrz8/whatsapp-api/pkg/phone"
 	"go.mau.fi/whatsmeow/types"
 )
 
@@ -20,31 +20,20 @@ func IsGroupJid(jid string) bool {
 	return strings.Contains(jid, "@g.us")
 }
 
-func IsOnWhatsapp(waCli *whatsmeow.Client, jid string) bool {
-	if strings.Contains(jid, "@s.whatsapp.net") {
-		data, err := waCli.IsOnWhatsApp([]string{jid})
-		if err != nil {
-			fmt.Println("recipient not exist")
-			return false
-		}
-
-		for _, v := range data {
-			if !v.IsIn {
-				return false
-			}
-		}
-	}
-
-	return true
-}
-
+// ParseJID accepts a JID or an international phone number, see phone.Normalize for the accepted formats.
 func ParseJID(arg string) (types.JID, error) {
-	if arg[0] == '+' {
-		arg = arg[1:]
+	arg = strings.TrimSpace(arg)
+	if arg == "" {
+		return types.EmptyJID, ErrRecipientNotFound
 	}
 	if !strings.ContainsRune(arg, '@') {
-		return types.NewJID(arg, types.DefaultUserServer), nil
+		number, err := phone.Normalize(arg, "")
+		if err != nil {
+			return types.EmptyJID, err
+		}
+		return types.NewJID(number, types.DefaultUserServer), nil
 	} else {
+		arg = strings.TrimPrefix(arg, "+")
 		recipient, err := types.ParseJID(arg)
 		if err != nil {
 			fmt.Printf("invalid JID %s: %v", arg, err)